@@ -0,0 +1,60 @@
+package main
+
+import (
+	"avitoBootcamp/internal/config"
+	"avitoBootcamp/internal/storage/redis"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func cacheCommand(ctx context.Context, cfg config.Config, args []string) error {
+	name, args, err := subcommand(args, `inspect`, `flush`)
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet(`cache `+name, flag.ContinueOnError)
+	houseId := flags.Int64(`house`, 0, `id of the house, all houses when omitted`)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *houseId < 0 {
+		return fmt.Errorf("-house must be positive")
+	}
+
+	cache, err := redis.New(cfg.Redis)
+	if err != nil {
+		return err
+	}
+
+	defer cache.Client.Close()
+
+	if name == `flush` {
+		deleted, err := cache.FlushFlatsCache(ctx, *houseId)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%d keys deleted\n", deleted)
+		return nil
+	}
+
+	entries, err := cache.FlatsCacheEntries(ctx, *houseId)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tTTL")
+
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\n", entry.Key, entry.TTL)
+	}
+
+	return w.Flush()
+}
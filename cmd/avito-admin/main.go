@@ -0,0 +1,86 @@
+// Command avito-admin operates the service: it migrates and seeds the
+// database, manages users and inspects or flushes the flats cache.
+package main
+
+import (
+	"avitoBootcamp/internal/config"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+const usage = `usage: avito-admin <command> [flags]
+
+The connection settings are read like the server reads them, see config.Load.
+
+commands:
+  migrate up                     apply the pending migrations
+  migrate down [-steps N]        revert the last N migrations (1 by default)
+  migrate status                 list the migrations and which are applied
+  seed                           add the demo houses and flats to an empty database
+  create-moderator -email E      create a moderator, the password is read from stdin
+  reset-password -email E        set a new password, read from stdin, and log the user out
+  promote -email E               make a client a moderator
+  demote -email E                make a moderator a client
+  cache inspect [-house ID]      list the cached pages of house flats
+  cache flush [-house ID]        drop the cached pages of house flats
+`
+
+type command func(ctx context.Context, cfg config.Config, args []string) error
+
+var commands = map[string]command{
+	`migrate`:          migrateCommand,
+	`seed`:             seedCommand,
+	`create-moderator`: createModeratorCommand,
+	`reset-password`:   resetPasswordCommand,
+	`promote`:          promoteCommand,
+	`demote`:           demoteCommand,
+	`cache`:            cacheCommand,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "avito-admin: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Ctrl-C cancels the command, whether it runs a query, waits for the
+	// migration lock or for the password on stdin.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err = run(ctx, cfg, os.Args[2:])
+	stop()
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "avito-admin %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// subcommand splits "up -steps 2" into the subcommand and its flags.
+func subcommand(args []string, known ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return ``, nil, fmt.Errorf("expected one of %s", strings.Join(known, `, `))
+	}
+
+	for _, name := range known {
+		if args[0] == name {
+			return name, args[1:], nil
+		}
+	}
+
+	return ``, nil, fmt.Errorf("unknown subcommand %q, expected one of %s", args[0], strings.Join(known, `, `))
+}
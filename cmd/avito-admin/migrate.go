@@ -0,0 +1,101 @@
+package main
+
+import (
+	"avitoBootcamp/internal/config"
+	"avitoBootcamp/internal/storage/postgres"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+func migrateCommand(ctx context.Context, cfg config.Config, args []string) error {
+	name, args, err := subcommand(args, `up`, `down`, `status`)
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet(`migrate `+name, flag.ContinueOnError)
+	steps := 1
+	if name == `down` {
+		flags.IntVar(&steps, `steps`, 1, `number of migrations to revert`)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Connect does not migrate, so the database is left as the command finds it.
+	database, err := postgres.Connect(cfg.Postgres)
+	if err != nil {
+		return err
+	}
+
+	defer database.Db.Close()
+
+	switch name {
+	case `up`:
+		if err := database.MigrateUp(ctx); err != nil {
+			return err
+		}
+	case `down`:
+		if steps < 1 {
+			return fmt.Errorf("-steps must be positive")
+		}
+
+		if err := database.MigrateDown(ctx, steps); err != nil {
+			return err
+		}
+	}
+
+	return printMigrationStatus(ctx, database)
+}
+
+func printMigrationStatus(ctx context.Context, database *postgres.Storage) error {
+	states, err := database.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+
+	for _, state := range states {
+		appliedAt := `pending`
+		if state.AppliedAt != nil {
+			appliedAt = state.AppliedAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\n", state.Version, state.Name, appliedAt)
+	}
+
+	return w.Flush()
+}
+
+func seedCommand(ctx context.Context, cfg config.Config, args []string) error {
+	if err := flag.NewFlagSet(`seed`, flag.ContinueOnError).Parse(args); err != nil {
+		return err
+	}
+
+	database, err := postgres.New(ctx, cfg.Postgres)
+	if err != nil {
+		return err
+	}
+
+	defer database.Db.Close()
+
+	seeded, err := database.Seed(ctx)
+	if err != nil {
+		return err
+	}
+
+	if seeded {
+		fmt.Println(`demo data added`)
+	} else {
+		fmt.Println(`the database already has houses, nothing added`)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"avitoBootcamp/internal/auth"
+	"avitoBootcamp/internal/config"
+	"avitoBootcamp/internal/models"
+	"avitoBootcamp/internal/storage"
+	"avitoBootcamp/internal/storage/postgres"
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func createModeratorCommand(ctx context.Context, cfg config.Config, args []string) error {
+	email, err := parseEmail(`create-moderator`, args)
+	if err != nil {
+		return err
+	}
+
+	password, err := readPassword(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The same hashing as on /register, so the moderator can log in as usual.
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return withDatabase(cfg, func(database *postgres.Storage) error {
+		user, err := database.CreateUser(ctx, models.User{Email: email, Password: passwordHash, UserType: `moderator`})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("moderator %s created with id %s\n", user.Email, user.Id)
+		return nil
+	})
+}
+
+func resetPasswordCommand(ctx context.Context, cfg config.Config, args []string) error {
+	email, err := parseEmail(`reset-password`, args)
+	if err != nil {
+		return err
+	}
+
+	password, err := readPassword(ctx)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return withUser(ctx, cfg, email, func(database *postgres.Storage, user models.User) error {
+		if _, err := database.SetUserPassword(ctx, email, passwordHash); err != nil {
+			return err
+		}
+
+		fmt.Printf("password of %s changed and its refresh tokens revoked, access tokens issued before stay valid until they expire\n", email)
+		return nil
+	})
+}
+
+func promoteCommand(ctx context.Context, cfg config.Config, args []string) error {
+	return changeUserType(ctx, cfg, `promote`, args, `moderator`)
+}
+
+func demoteCommand(ctx context.Context, cfg config.Config, args []string) error {
+	return changeUserType(ctx, cfg, `demote`, args, `client`)
+}
+
+func changeUserType(ctx context.Context, cfg config.Config, name string, args []string, userType string) error {
+	email, err := parseEmail(name, args)
+	if err != nil {
+		return err
+	}
+
+	return withUser(ctx, cfg, email, func(database *postgres.Storage, user models.User) error {
+		if user.UserType == userType {
+			fmt.Printf("%s is already a %s\n", email, userType)
+			return nil
+		}
+
+		if _, err := database.SetUserType(ctx, email, userType); err != nil {
+			return err
+		}
+
+		fmt.Printf("%s is now a %s\n", email, userType)
+		return nil
+	})
+}
+
+func parseEmail(name string, args []string) (string, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	email := flags.String(`email`, ``, `email of the user`)
+
+	if err := flags.Parse(args); err != nil {
+		return ``, err
+	}
+
+	if *email == `` {
+		return ``, errors.New(`-email is required`)
+	}
+
+	return *email, nil
+}
+
+// readPassword reads the password from the first line of stdin, so it does
+// not end up in the shell history or the process list.
+func readPassword(ctx context.Context) (string, error) {
+	fmt.Fprint(os.Stderr, `password: `)
+
+	type result struct {
+		line string
+		err  error
+	}
+
+	read := make(chan result, 1)
+
+	// A read from stdin can not be interrupted, so it runs aside and a
+	// cancelled ctx returns without waiting for it.
+	go func() {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		read <- result{line, err}
+	}()
+
+	var line string
+
+	select {
+	case <-ctx.Done():
+		fmt.Fprintln(os.Stderr)
+		return ``, ctx.Err()
+	case r := <-read:
+		if r.err != nil && r.line == `` {
+			return ``, fmt.Errorf("could not read the password: %w", r.err)
+		}
+
+		line = r.line
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == `` {
+		return ``, errors.New(`the password is empty`)
+	}
+
+	return password, nil
+}
+
+// withDatabase runs against the schema as it is, migrations are left to the
+// migrate command.
+func withDatabase(cfg config.Config, run func(database *postgres.Storage) error) error {
+	database, err := postgres.Connect(cfg.Postgres)
+	if err != nil {
+		return err
+	}
+
+	defer database.Db.Close()
+
+	return run(database)
+}
+
+// withUser finds the user by email. The dummy users behind /dummyLogin are
+// refused, their type is fixed and they have no password.
+func withUser(ctx context.Context, cfg config.Config, email string, run func(database *postgres.Storage, user models.User) error) error {
+	return withDatabase(cfg, func(database *postgres.Storage) error {
+		user, err := database.GetUserByEmail(ctx, email)
+
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("no user with email %s", email)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if user.Id == models.DummyClientId || user.Id == models.DummyModeratorId {
+			return fmt.Errorf("%s is a dummy login user and can not be changed", email)
+		}
+
+		return run(database, user)
+	})
+}
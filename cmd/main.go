@@ -1,26 +1,57 @@
 package main
 
 import (
+	"avitoBootcamp/internal/auth"
+	"avitoBootcamp/internal/config"
+	"avitoBootcamp/internal/outbox"
 	"avitoBootcamp/internal/router"
+	"avitoBootcamp/internal/sender"
 	"avitoBootcamp/internal/storage/postgres"
 	"avitoBootcamp/internal/storage/redis"
+	"context"
+	"errors"
 	"log"
 	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	database, err := postgres.New()
+	cfg, err := config.Load()
 
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	defer database.Db.Close()
+	// The context is cancelled on SIGINT or SIGTERM, which stops the startup
+	// or, once the server runs, starts the shutdown.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	database, err := postgres.New(ctx, cfg.Postgres)
+
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	slog.Info("Successfully connected to the database!")
 
-	redisClient, err := redis.New()
+	// Demo data is for development and the integration tests only.
+	if cfg.Postgres.Seed {
+		seeded, err := database.Seed(ctx)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if seeded {
+			slog.Info(`Demo data added to the database`)
+		}
+	}
+
+	redisClient, err := redis.New(cfg.Redis)
 
 	if err != nil {
 		log.Fatal(err)
@@ -28,8 +59,70 @@ func main() {
 
 	slog.Info(`Successfully connected to the redis client!`)
 
-	handler := router.New(database, redisClient)
+	keys, err := auth.Load(cfg.JWT)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+
+	worker := outbox.NewWorker(database, redisClient, sender.New(os.Stdout))
+	go func() {
+		defer close(workerDone)
+		worker.Run(workerCtx)
+	}()
 
-	log.Fatal(http.ListenAndServe(`:8080`, handler))
+	server := &http.Server{
+		Addr:              cfg.HTTP.Addr,
+		Handler:           router.New(database, redisClient, keys, cfg.Auth),
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info(`Listening`, `addr`, cfg.HTTP.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	exitCode := 0
+
+	select {
+	case err := <-serverErr:
+		slog.Error(`Server stopped`, slog.Any(`err`, err))
+		exitCode = 1
+	case <-ctx.Done():
+		slog.Info(`Shutting down, waiting for requests in flight`, `timeout`, cfg.HTTP.ShutdownTimeout)
+	}
+
+	// Requests in flight finish before the storage they use is closed. Past
+	// the deadline the remaining connections are cut.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error(`Requests did not finish in time`, slog.Any(`err`, err))
+		server.Close()
+	}
 
+	stopWorker()
+	<-workerDone
+
+	if err := redisClient.Client.Close(); err != nil {
+		slog.Error(`Failed to close the redis client`, slog.Any(`err`, err))
+	}
+
+	if err := database.Db.Close(); err != nil {
+		slog.Error(`Failed to close the database`, slog.Any(`err`, err))
+	}
+
+	slog.Info(`Stopped`)
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
 }
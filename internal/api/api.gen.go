@@ -0,0 +1,4638 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"avitoBootcamp/internal/apierror"
+	"avitoBootcamp/internal/models"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for Status.
+const (
+	StatusApproved     Status = "approved"
+	StatusCreated      Status = "created"
+	StatusDeclined     Status = "declined"
+	StatusOnModeration Status = "on moderation"
+)
+
+// Defines values for UserType.
+const (
+	UserTypeClient    UserType = "client"
+	UserTypeModerator UserType = "moderator"
+)
+
+// Defines values for SearchFlatsParamsSort.
+const (
+	SearchFlatsParamsSortNewest    SearchFlatsParamsSort = "newest"
+	SearchFlatsParamsSortPriceAsc  SearchFlatsParamsSort = "price_asc"
+	SearchFlatsParamsSortPriceDesc SearchFlatsParamsSort = "price_desc"
+)
+
+// Defines values for GetHouseFlatsParamsSort.
+const (
+	GetHouseFlatsParamsSortFlatNum GetHouseFlatsParamsSort = "flat_num"
+	GetHouseFlatsParamsSortPrice   GetHouseFlatsParamsSort = "price"
+	GetHouseFlatsParamsSortRooms   GetHouseFlatsParamsSort = "rooms"
+)
+
+// Address Адрес дома
+type Address = string
+
+// Date Дата + время
+type Date = time.Time
+
+// DeclineReason Причина отклонения квартиры
+type DeclineReason = models.DeclineReason
+
+// DeclineReasonCode Код причины отклонения квартиры
+type DeclineReasonCode = string
+
+// Developer Застройщик
+type Developer = string
+
+// Email Email пользователя
+type Email = openapi_types.Email
+
+// Error defines model for Error.
+type Error = apierror.Response
+
+// Flat Квартира
+type Flat = models.Flat
+
+// FlatId Идентификатор квартиры
+type FlatId = int64
+
+// FlatStatusChange Изменение статуса квартиры
+type FlatStatusChange = models.FlatStatusChange
+
+// Health defines model for Health.
+type Health struct {
+	Status string `json:"status"`
+}
+
+// House Дом
+type House = models.House
+
+// HouseId Идентификатор дома
+type HouseId = int64
+
+// Password Пароль пользователя
+type Password = string
+
+// Price Цена квартиры в у.е.
+type Price = int64
+
+// RefreshToken Токен для получения нового авторизационного токена
+type RefreshToken = string
+
+// Rooms Количество комнат в квартире
+type Rooms = int
+
+// Status Статус квартиры
+type Status string
+
+// Token Авторизационный токен
+type Token = string
+
+// UserId Идентификатор пользователя
+type UserId = string
+
+// UserType Тип пользователя
+type UserType string
+
+// Year Год постройки дома
+type Year = int
+
+// QueueDeveloper defines model for QueueDeveloper.
+type QueueDeveloper = string
+
+// QueueHouseId Идентификатор дома
+type QueueHouseId = HouseId
+
+// QueuePriceMax Цена квартиры в у.е.
+type QueuePriceMax = Price
+
+// QueuePriceMin Цена квартиры в у.е.
+type QueuePriceMin = Price
+
+// N400 defines model for 400.
+type N400 = Error
+
+// N401 defines model for 401.
+type N401 = Error
+
+// N403 defines model for 403.
+type N403 = Error
+
+// N5xx defines model for 5xx.
+type N5xx = Error
+
+// DummyLoginParams defines parameters for DummyLogin.
+type DummyLoginParams struct {
+	UserType UserType `form:"user_type" json:"user_type"`
+}
+
+// CreateFlatJSONBody defines parameters for CreateFlat.
+type CreateFlatJSONBody = models.Flat
+
+// UpdateFlatJSONBody defines parameters for UpdateFlat.
+type UpdateFlatJSONBody = models.Flat
+
+// EditFlatJSONBody defines parameters for EditFlat.
+type EditFlatJSONBody = models.FlatEdit
+
+// ResubmitFlatJSONBody defines parameters for ResubmitFlat.
+type ResubmitFlatJSONBody = models.FlatEdit
+
+// SearchFlatsParams defines parameters for SearchFlats.
+type SearchFlatsParams struct {
+	PriceMin *Price `form:"price_min,omitempty" json:"price_min,omitempty"`
+	PriceMax *Price `form:"price_max,omitempty" json:"price_max,omitempty"`
+	RoomsMin *Rooms `form:"rooms_min,omitempty" json:"rooms_min,omitempty"`
+	RoomsMax *Rooms `form:"rooms_max,omitempty" json:"rooms_max,omitempty"`
+
+	// YearMin Минимальный год постройки дома
+	YearMin *Year `form:"year_min,omitempty" json:"year_min,omitempty"`
+
+	// YearMax Максимальный год постройки дома
+	YearMax   *Year   `form:"year_max,omitempty" json:"year_max,omitempty"`
+	Developer *string `form:"developer,omitempty" json:"developer,omitempty"`
+
+	// Address Часть адреса дома, без учета регистра
+	Address *string `form:"address,omitempty" json:"address,omitempty"`
+
+	// Sort Порядок квартир: newest - сначала новые, price_asc - по возрастанию цены, price_desc - по убыванию цены
+	Sort  *SearchFlatsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+	Limit *int                   `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы, выданный для той же сортировки
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// SearchFlatsParamsSort defines parameters for SearchFlats.
+type SearchFlatsParamsSort string
+
+// CreateHouseJSONBody defines parameters for CreateHouse.
+type CreateHouseJSONBody = models.House
+
+// GetHouseFlatsParams defines parameters for GetHouseFlats.
+type GetHouseFlatsParams struct {
+	// IncludeDeleted Вернуть также квартиры в архиве. Учитывается только для модераторов
+	IncludeDeleted *bool  `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+	RoomsMin       *Rooms `form:"rooms_min,omitempty" json:"rooms_min,omitempty"`
+	RoomsMax       *Rooms `form:"rooms_max,omitempty" json:"rooms_max,omitempty"`
+	PriceMin       *Price `form:"price_min,omitempty" json:"price_min,omitempty"`
+	PriceMax       *Price `form:"price_max,omitempty" json:"price_max,omitempty"`
+
+	// Sort Поле, по возрастанию которого упорядочены квартиры
+	Sort *GetHouseFlatsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Limit Размер страницы. Без него возвращаются все квартиры дома
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы, выданный для той же сортировки
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetHouseFlatsParamsSort defines parameters for GetHouseFlats.
+type GetHouseFlatsParamsSort string
+
+// EditHouseJSONBody defines parameters for EditHouse.
+type EditHouseJSONBody = models.HouseEdit
+
+// SubscribeJSONBody defines parameters for Subscribe.
+type SubscribeJSONBody = models.Subscription
+
+// ListHousesParams defines parameters for ListHouses.
+type ListHousesParams struct {
+	Developer *string `form:"developer,omitempty" json:"developer,omitempty"`
+	YearMin   *Year   `form:"year_min,omitempty" json:"year_min,omitempty"`
+	YearMax   *Year   `form:"year_max,omitempty" json:"year_max,omitempty"`
+
+	// UpdatedSince Только дома, в которых после этого времени появились квартиры
+	UpdatedSince *time.Time `form:"updated_since,omitempty" json:"updated_since,omitempty"`
+	Limit        *int       `form:"limit,omitempty" json:"limit,omitempty"`
+	Cursor       *string    `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// LoginJSONBody defines parameters for Login.
+type LoginJSONBody = models.User
+
+// LogoutJSONBody defines parameters for Logout.
+type LogoutJSONBody = models.RefreshRequest
+
+// GetModerationQueueParams defines parameters for GetModerationQueue.
+type GetModerationQueueParams struct {
+	HouseId   *QueueHouseId   `form:"house_id,omitempty" json:"house_id,omitempty"`
+	Developer *QueueDeveloper `form:"developer,omitempty" json:"developer,omitempty"`
+	PriceMin  *QueuePriceMin  `form:"price_min,omitempty" json:"price_min,omitempty"`
+	PriceMax  *QueuePriceMax  `form:"price_max,omitempty" json:"price_max,omitempty"`
+	Limit     *int            `form:"limit,omitempty" json:"limit,omitempty"`
+	Cursor    *string         `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// TakeNextFlatParams defines parameters for TakeNextFlat.
+type TakeNextFlatParams struct {
+	HouseId   *QueueHouseId   `form:"house_id,omitempty" json:"house_id,omitempty"`
+	Developer *QueueDeveloper `form:"developer,omitempty" json:"developer,omitempty"`
+	PriceMin  *QueuePriceMin  `form:"price_min,omitempty" json:"price_min,omitempty"`
+	PriceMax  *QueuePriceMax  `form:"price_max,omitempty" json:"price_max,omitempty"`
+}
+
+// RegisterJSONBody defines parameters for Register.
+type RegisterJSONBody = models.User
+
+// RefreshTokenJSONBody defines parameters for RefreshToken.
+type RefreshTokenJSONBody = models.RefreshRequest
+
+// SaveDeclineReasonJSONRequestBody defines body for SaveDeclineReason for application/json ContentType.
+type SaveDeclineReasonJSONRequestBody = DeclineReason
+
+// CreateFlatJSONRequestBody defines body for CreateFlat for application/json ContentType.
+type CreateFlatJSONRequestBody = CreateFlatJSONBody
+
+// UpdateFlatJSONRequestBody defines body for UpdateFlat for application/json ContentType.
+type UpdateFlatJSONRequestBody = UpdateFlatJSONBody
+
+// EditFlatJSONRequestBody defines body for EditFlat for application/json ContentType.
+type EditFlatJSONRequestBody = EditFlatJSONBody
+
+// ResubmitFlatJSONRequestBody defines body for ResubmitFlat for application/json ContentType.
+type ResubmitFlatJSONRequestBody = ResubmitFlatJSONBody
+
+// CreateHouseJSONRequestBody defines body for CreateHouse for application/json ContentType.
+type CreateHouseJSONRequestBody = CreateHouseJSONBody
+
+// EditHouseJSONRequestBody defines body for EditHouse for application/json ContentType.
+type EditHouseJSONRequestBody = EditHouseJSONBody
+
+// SubscribeJSONRequestBody defines body for Subscribe for application/json ContentType.
+type SubscribeJSONRequestBody = SubscribeJSONBody
+
+// LoginJSONRequestBody defines body for Login for application/json ContentType.
+type LoginJSONRequestBody = LoginJSONBody
+
+// LogoutJSONRequestBody defines body for Logout for application/json ContentType.
+type LogoutJSONRequestBody = LogoutJSONBody
+
+// RegisterJSONRequestBody defines body for Register for application/json ContentType.
+type RegisterJSONRequestBody = RegisterJSONBody
+
+// RefreshTokenJSONRequestBody defines body for RefreshToken for application/json ContentType.
+type RefreshTokenJSONRequestBody = RefreshTokenJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+
+	// (GET /decline-reasons)
+	ListDeclineReasons(w http.ResponseWriter, r *http.Request)
+
+	// (POST /decline-reasons)
+	SaveDeclineReason(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /decline-reasons/{code})
+	DeleteDeclineReason(w http.ResponseWriter, r *http.Request, code DeclineReasonCode)
+
+	// (GET /dummyLogin)
+	DummyLogin(w http.ResponseWriter, r *http.Request, params DummyLoginParams)
+
+	// (POST /flat/create)
+	CreateFlat(w http.ResponseWriter, r *http.Request)
+
+	// (POST /flat/update)
+	UpdateFlat(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /flat/{id})
+	DeleteFlat(w http.ResponseWriter, r *http.Request, id FlatId)
+
+	// (PATCH /flat/{id})
+	EditFlat(w http.ResponseWriter, r *http.Request, id FlatId)
+
+	// (POST /flat/{id}/extend)
+	ExtendFlat(w http.ResponseWriter, r *http.Request, id FlatId)
+
+	// (GET /flat/{id}/history)
+	GetFlatHistory(w http.ResponseWriter, r *http.Request, id FlatId)
+
+	// (POST /flat/{id}/release)
+	ReleaseFlat(w http.ResponseWriter, r *http.Request, id FlatId)
+
+	// (POST /flat/{id}/restore)
+	RestoreFlat(w http.ResponseWriter, r *http.Request, id FlatId)
+
+	// (POST /flat/{id}/resubmit)
+	ResubmitFlat(w http.ResponseWriter, r *http.Request, id FlatId)
+
+	// (GET /flats/search)
+	SearchFlats(w http.ResponseWriter, r *http.Request, params SearchFlatsParams)
+
+	// (GET /healthz)
+	Healthz(w http.ResponseWriter, r *http.Request)
+
+	// (POST /house/create)
+	CreateHouse(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /house/{id})
+	DeleteHouse(w http.ResponseWriter, r *http.Request, id HouseId)
+
+	// (GET /house/{id})
+	GetHouseFlats(w http.ResponseWriter, r *http.Request, id HouseId, params GetHouseFlatsParams)
+
+	// (PATCH /house/{id})
+	EditHouse(w http.ResponseWriter, r *http.Request, id HouseId)
+
+	// (GET /house/{id}/info)
+	GetHouse(w http.ResponseWriter, r *http.Request, id HouseId)
+
+	// (POST /house/{id}/restore)
+	RestoreHouse(w http.ResponseWriter, r *http.Request, id HouseId)
+
+	// (POST /house/{id}/subscribe)
+	Subscribe(w http.ResponseWriter, r *http.Request, id HouseId)
+
+	// (GET /houses)
+	ListHouses(w http.ResponseWriter, r *http.Request, params ListHousesParams)
+
+	// (POST /login)
+	Login(w http.ResponseWriter, r *http.Request)
+
+	// (POST /logout)
+	Logout(w http.ResponseWriter, r *http.Request)
+
+	// (GET /me/flats)
+	GetMyFlats(w http.ResponseWriter, r *http.Request)
+
+	// (GET /moderation/queue)
+	GetModerationQueue(w http.ResponseWriter, r *http.Request, params GetModerationQueueParams)
+
+	// (POST /moderation/queue/next)
+	TakeNextFlat(w http.ResponseWriter, r *http.Request, params TakeNextFlatParams)
+
+	// (GET /readyz)
+	Readyz(w http.ResponseWriter, r *http.Request)
+
+	// (POST /register)
+	Register(w http.ResponseWriter, r *http.Request)
+
+	// (POST /token/refresh)
+	RefreshToken(w http.ResponseWriter, r *http.Request)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListDeclineReasons operation middleware
+func (siw *ServerInterfaceWrapper) ListDeclineReasons(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListDeclineReasons(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SaveDeclineReason operation middleware
+func (siw *ServerInterfaceWrapper) SaveDeclineReason(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SaveDeclineReason(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteDeclineReason operation middleware
+func (siw *ServerInterfaceWrapper) DeleteDeclineReason(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "code" -------------
+	var code DeclineReasonCode
+
+	err = runtime.BindStyledParameterWithOptions("simple", "code", mux.Vars(r)["code"], &code, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "code", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteDeclineReason(w, r, code)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DummyLogin operation middleware
+func (siw *ServerInterfaceWrapper) DummyLogin(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DummyLoginParams
+
+	// ------------- Required query parameter "user_type" -------------
+
+	if paramValue := r.URL.Query().Get("user_type"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_type"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_type", r.URL.Query(), &params.UserType)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_type", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DummyLogin(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateFlat operation middleware
+func (siw *ServerInterfaceWrapper) CreateFlat(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateFlat(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateFlat operation middleware
+func (siw *ServerInterfaceWrapper) UpdateFlat(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateFlat(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteFlat operation middleware
+func (siw *ServerInterfaceWrapper) DeleteFlat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FlatId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteFlat(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// EditFlat operation middleware
+func (siw *ServerInterfaceWrapper) EditFlat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FlatId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EditFlat(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExtendFlat operation middleware
+func (siw *ServerInterfaceWrapper) ExtendFlat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FlatId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExtendFlat(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetFlatHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetFlatHistory(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FlatId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFlatHistory(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ReleaseFlat operation middleware
+func (siw *ServerInterfaceWrapper) ReleaseFlat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FlatId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReleaseFlat(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RestoreFlat operation middleware
+func (siw *ServerInterfaceWrapper) RestoreFlat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FlatId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RestoreFlat(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ResubmitFlat operation middleware
+func (siw *ServerInterfaceWrapper) ResubmitFlat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FlatId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResubmitFlat(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SearchFlats operation middleware
+func (siw *ServerInterfaceWrapper) SearchFlats(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchFlatsParams
+
+	// ------------- Optional query parameter "price_min" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_min", r.URL.Query(), &params.PriceMin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_min", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_max" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_max", r.URL.Query(), &params.PriceMax)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_max", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "rooms_min" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "rooms_min", r.URL.Query(), &params.RoomsMin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "rooms_min", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "rooms_max" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "rooms_max", r.URL.Query(), &params.RoomsMax)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "rooms_max", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "year_min" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "year_min", r.URL.Query(), &params.YearMin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "year_min", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "year_max" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "year_max", r.URL.Query(), &params.YearMax)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "year_max", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "developer" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "developer", r.URL.Query(), &params.Developer)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "developer", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "address" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "address", r.URL.Query(), &params.Address)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "address", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SearchFlats(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// Healthz operation middleware
+func (siw *ServerInterfaceWrapper) Healthz(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Healthz(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateHouse operation middleware
+func (siw *ServerInterfaceWrapper) CreateHouse(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateHouse(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteHouse operation middleware
+func (siw *ServerInterfaceWrapper) DeleteHouse(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id HouseId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteHouse(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHouseFlats operation middleware
+func (siw *ServerInterfaceWrapper) GetHouseFlats(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id HouseId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetHouseFlatsParams
+
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "rooms_min" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "rooms_min", r.URL.Query(), &params.RoomsMin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "rooms_min", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "rooms_max" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "rooms_max", r.URL.Query(), &params.RoomsMax)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "rooms_max", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_min" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_min", r.URL.Query(), &params.PriceMin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_min", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_max" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_max", r.URL.Query(), &params.PriceMax)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_max", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHouseFlats(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// EditHouse operation middleware
+func (siw *ServerInterfaceWrapper) EditHouse(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id HouseId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EditHouse(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHouse operation middleware
+func (siw *ServerInterfaceWrapper) GetHouse(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id HouseId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHouse(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RestoreHouse operation middleware
+func (siw *ServerInterfaceWrapper) RestoreHouse(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id HouseId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RestoreHouse(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// Subscribe operation middleware
+func (siw *ServerInterfaceWrapper) Subscribe(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id HouseId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Subscribe(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListHouses operation middleware
+func (siw *ServerInterfaceWrapper) ListHouses(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListHousesParams
+
+	// ------------- Optional query parameter "developer" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "developer", r.URL.Query(), &params.Developer)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "developer", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "year_min" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "year_min", r.URL.Query(), &params.YearMin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "year_min", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "year_max" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "year_max", r.URL.Query(), &params.YearMax)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "year_max", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "updated_since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "updated_since", r.URL.Query(), &params.UpdatedSince)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "updated_since", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListHouses(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// Login operation middleware
+func (siw *ServerInterfaceWrapper) Login(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Login(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// Logout operation middleware
+func (siw *ServerInterfaceWrapper) Logout(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Logout(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetMyFlats operation middleware
+func (siw *ServerInterfaceWrapper) GetMyFlats(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMyFlats(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetModerationQueue operation middleware
+func (siw *ServerInterfaceWrapper) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetModerationQueueParams
+
+	// ------------- Optional query parameter "house_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "house_id", r.URL.Query(), &params.HouseId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "house_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "developer" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "developer", r.URL.Query(), &params.Developer)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "developer", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_min" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_min", r.URL.Query(), &params.PriceMin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_min", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_max" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_max", r.URL.Query(), &params.PriceMax)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_max", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetModerationQueue(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// TakeNextFlat operation middleware
+func (siw *ServerInterfaceWrapper) TakeNextFlat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"moderator"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TakeNextFlatParams
+
+	// ------------- Optional query parameter "house_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "house_id", r.URL.Query(), &params.HouseId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "house_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "developer" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "developer", r.URL.Query(), &params.Developer)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "developer", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_min" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_min", r.URL.Query(), &params.PriceMin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_min", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_max" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_max", r.URL.Query(), &params.PriceMax)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_max", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.TakeNextFlat(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// Readyz operation middleware
+func (siw *ServerInterfaceWrapper) Readyz(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Readyz(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// Register operation middleware
+func (siw *ServerInterfaceWrapper) Register(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Register(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RefreshToken operation middleware
+func (siw *ServerInterfaceWrapper) RefreshToken(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RefreshToken(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, GorillaServerOptions{})
+}
+
+type GorillaServerOptions struct {
+	BaseURL          string
+	BaseRouter       *mux.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r *mux.Router) http.Handler {
+	return HandlerWithOptions(si, GorillaServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r *mux.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, GorillaServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = mux.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.HandleFunc(options.BaseURL+"/decline-reasons", wrapper.ListDeclineReasons).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/decline-reasons", wrapper.SaveDeclineReason).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/decline-reasons/{code}", wrapper.DeleteDeclineReason).Methods("DELETE")
+
+	r.HandleFunc(options.BaseURL+"/dummyLogin", wrapper.DummyLogin).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/flat/create", wrapper.CreateFlat).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/flat/update", wrapper.UpdateFlat).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/flat/{id}", wrapper.DeleteFlat).Methods("DELETE")
+
+	r.HandleFunc(options.BaseURL+"/flat/{id}", wrapper.EditFlat).Methods("PATCH")
+
+	r.HandleFunc(options.BaseURL+"/flat/{id}/extend", wrapper.ExtendFlat).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/flat/{id}/history", wrapper.GetFlatHistory).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/flat/{id}/release", wrapper.ReleaseFlat).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/flat/{id}/restore", wrapper.RestoreFlat).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/flat/{id}/resubmit", wrapper.ResubmitFlat).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/flats/search", wrapper.SearchFlats).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/healthz", wrapper.Healthz).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/house/create", wrapper.CreateHouse).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/house/{id}", wrapper.DeleteHouse).Methods("DELETE")
+
+	r.HandleFunc(options.BaseURL+"/house/{id}", wrapper.GetHouseFlats).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/house/{id}", wrapper.EditHouse).Methods("PATCH")
+
+	r.HandleFunc(options.BaseURL+"/house/{id}/info", wrapper.GetHouse).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/house/{id}/restore", wrapper.RestoreHouse).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/house/{id}/subscribe", wrapper.Subscribe).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/houses", wrapper.ListHouses).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/login", wrapper.Login).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/logout", wrapper.Logout).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/me/flats", wrapper.GetMyFlats).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/moderation/queue", wrapper.GetModerationQueue).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/moderation/queue/next", wrapper.TakeNextFlat).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/readyz", wrapper.Readyz).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/register", wrapper.Register).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/token/refresh", wrapper.RefreshToken).Methods("POST")
+
+	return r
+}
+
+type N400ResponseHeaders struct {
+	XRequestId string
+}
+type N400JSONResponse struct {
+	Body Error
+
+	Headers N400ResponseHeaders
+}
+
+type N401ResponseHeaders struct {
+	XRequestId string
+}
+type N401JSONResponse struct {
+	Body Error
+
+	Headers N401ResponseHeaders
+}
+
+type N403ResponseHeaders struct {
+	XRequestId string
+}
+type N403JSONResponse struct {
+	Body Error
+
+	Headers N403ResponseHeaders
+}
+
+type N5xxResponseHeaders struct {
+	RetryAfter int
+	XRequestId string
+}
+type N5xxJSONResponse struct {
+	Body Error
+
+	Headers N5xxResponseHeaders
+}
+
+type ListDeclineReasonsRequestObject struct {
+}
+
+type ListDeclineReasonsResponseObject interface {
+	VisitListDeclineReasonsResponse(w http.ResponseWriter) error
+}
+
+type ListDeclineReasons200JSONResponse struct {
+	Reasons []DeclineReason `json:"reasons"`
+}
+
+func (response ListDeclineReasons200JSONResponse) VisitListDeclineReasonsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListDeclineReasons401JSONResponse struct{ N401JSONResponse }
+
+func (response ListDeclineReasons401JSONResponse) VisitListDeclineReasonsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListDeclineReasons403JSONResponse struct{ N403JSONResponse }
+
+func (response ListDeclineReasons403JSONResponse) VisitListDeclineReasonsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListDeclineReasons500JSONResponse struct{ N5xxJSONResponse }
+
+func (response ListDeclineReasons500JSONResponse) VisitListDeclineReasonsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SaveDeclineReasonRequestObject struct {
+	Body *SaveDeclineReasonJSONRequestBody
+}
+
+type SaveDeclineReasonResponseObject interface {
+	VisitSaveDeclineReasonResponse(w http.ResponseWriter) error
+}
+
+type SaveDeclineReason200JSONResponse DeclineReason
+
+func (response SaveDeclineReason200JSONResponse) VisitSaveDeclineReasonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SaveDeclineReason400JSONResponse struct{ N400JSONResponse }
+
+func (response SaveDeclineReason400JSONResponse) VisitSaveDeclineReasonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SaveDeclineReason401JSONResponse struct{ N401JSONResponse }
+
+func (response SaveDeclineReason401JSONResponse) VisitSaveDeclineReasonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SaveDeclineReason403JSONResponse struct{ N403JSONResponse }
+
+func (response SaveDeclineReason403JSONResponse) VisitSaveDeclineReasonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SaveDeclineReason500JSONResponse struct{ N5xxJSONResponse }
+
+func (response SaveDeclineReason500JSONResponse) VisitSaveDeclineReasonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteDeclineReasonRequestObject struct {
+	Code DeclineReasonCode `json:"code"`
+}
+
+type DeleteDeclineReasonResponseObject interface {
+	VisitDeleteDeclineReasonResponse(w http.ResponseWriter) error
+}
+
+type DeleteDeclineReason200Response struct {
+}
+
+func (response DeleteDeclineReason200Response) VisitDeleteDeclineReasonResponse(w http.ResponseWriter) error {
+	w.WriteHeader(200)
+	return nil
+}
+
+type DeleteDeclineReason401JSONResponse struct{ N401JSONResponse }
+
+func (response DeleteDeclineReason401JSONResponse) VisitDeleteDeclineReasonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteDeclineReason403JSONResponse struct{ N403JSONResponse }
+
+func (response DeleteDeclineReason403JSONResponse) VisitDeleteDeclineReasonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteDeclineReason404JSONResponse Error
+
+func (response DeleteDeclineReason404JSONResponse) VisitDeleteDeclineReasonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteDeclineReason500JSONResponse struct{ N5xxJSONResponse }
+
+func (response DeleteDeclineReason500JSONResponse) VisitDeleteDeclineReasonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DummyLoginRequestObject struct {
+	Params DummyLoginParams
+}
+
+type DummyLoginResponseObject interface {
+	VisitDummyLoginResponse(w http.ResponseWriter) error
+}
+
+type DummyLogin200JSONResponse models.AuthorizationToken
+
+func (response DummyLogin200JSONResponse) VisitDummyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DummyLogin400JSONResponse struct{ N400JSONResponse }
+
+func (response DummyLogin400JSONResponse) VisitDummyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DummyLogin500JSONResponse struct{ N5xxJSONResponse }
+
+func (response DummyLogin500JSONResponse) VisitDummyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateFlatRequestObject struct {
+	Body *CreateFlatJSONRequestBody
+}
+
+type CreateFlatResponseObject interface {
+	VisitCreateFlatResponse(w http.ResponseWriter) error
+}
+
+type CreateFlat200JSONResponse Flat
+
+func (response CreateFlat200JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response CreateFlat400JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response CreateFlat401JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateFlat404JSONResponse Error
+
+func (response CreateFlat404JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateFlat409JSONResponse Error
+
+func (response CreateFlat409JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response CreateFlat500JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateFlatRequestObject struct {
+	Body *UpdateFlatJSONRequestBody
+}
+
+type UpdateFlatResponseObject interface {
+	VisitUpdateFlatResponse(w http.ResponseWriter) error
+}
+
+type UpdateFlat200JSONResponse Flat
+
+func (response UpdateFlat200JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response UpdateFlat400JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response UpdateFlat401JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateFlat403JSONResponse struct{ N403JSONResponse }
+
+func (response UpdateFlat403JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateFlat404JSONResponse Error
+
+func (response UpdateFlat404JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateFlat409JSONResponse Error
+
+func (response UpdateFlat409JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response UpdateFlat500JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteFlatRequestObject struct {
+	Id FlatId `json:"id"`
+}
+
+type DeleteFlatResponseObject interface {
+	VisitDeleteFlatResponse(w http.ResponseWriter) error
+}
+
+type DeleteFlat200JSONResponse Flat
+
+func (response DeleteFlat200JSONResponse) VisitDeleteFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response DeleteFlat400JSONResponse) VisitDeleteFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response DeleteFlat401JSONResponse) VisitDeleteFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteFlat403JSONResponse struct{ N403JSONResponse }
+
+func (response DeleteFlat403JSONResponse) VisitDeleteFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteFlat404JSONResponse Error
+
+func (response DeleteFlat404JSONResponse) VisitDeleteFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response DeleteFlat500JSONResponse) VisitDeleteFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EditFlatRequestObject struct {
+	Id   FlatId `json:"id"`
+	Body *EditFlatJSONRequestBody
+}
+
+type EditFlatResponseObject interface {
+	VisitEditFlatResponse(w http.ResponseWriter) error
+}
+
+type EditFlat200JSONResponse Flat
+
+func (response EditFlat200JSONResponse) VisitEditFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EditFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response EditFlat400JSONResponse) VisitEditFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EditFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response EditFlat401JSONResponse) VisitEditFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EditFlat403JSONResponse struct{ N403JSONResponse }
+
+func (response EditFlat403JSONResponse) VisitEditFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EditFlat404JSONResponse Error
+
+func (response EditFlat404JSONResponse) VisitEditFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EditFlat409JSONResponse Error
+
+func (response EditFlat409JSONResponse) VisitEditFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EditFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response EditFlat500JSONResponse) VisitEditFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExtendFlatRequestObject struct {
+	Id FlatId `json:"id"`
+}
+
+type ExtendFlatResponseObject interface {
+	VisitExtendFlatResponse(w http.ResponseWriter) error
+}
+
+type ExtendFlat200JSONResponse Flat
+
+func (response ExtendFlat200JSONResponse) VisitExtendFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExtendFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response ExtendFlat400JSONResponse) VisitExtendFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExtendFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response ExtendFlat401JSONResponse) VisitExtendFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExtendFlat403JSONResponse struct{ N403JSONResponse }
+
+func (response ExtendFlat403JSONResponse) VisitExtendFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExtendFlat404JSONResponse Error
+
+func (response ExtendFlat404JSONResponse) VisitExtendFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExtendFlat409JSONResponse Error
+
+func (response ExtendFlat409JSONResponse) VisitExtendFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExtendFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response ExtendFlat500JSONResponse) VisitExtendFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetFlatHistoryRequestObject struct {
+	Id FlatId `json:"id"`
+}
+
+type GetFlatHistoryResponseObject interface {
+	VisitGetFlatHistoryResponse(w http.ResponseWriter) error
+}
+
+type GetFlatHistory200JSONResponse struct {
+	History []FlatStatusChange `json:"history"`
+}
+
+func (response GetFlatHistory200JSONResponse) VisitGetFlatHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFlatHistory400JSONResponse struct{ N400JSONResponse }
+
+func (response GetFlatHistory400JSONResponse) VisitGetFlatHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetFlatHistory401JSONResponse struct{ N401JSONResponse }
+
+func (response GetFlatHistory401JSONResponse) VisitGetFlatHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetFlatHistory403JSONResponse struct{ N403JSONResponse }
+
+func (response GetFlatHistory403JSONResponse) VisitGetFlatHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetFlatHistory404JSONResponse Error
+
+func (response GetFlatHistory404JSONResponse) VisitGetFlatHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFlatHistory500JSONResponse struct{ N5xxJSONResponse }
+
+func (response GetFlatHistory500JSONResponse) VisitGetFlatHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ReleaseFlatRequestObject struct {
+	Id FlatId `json:"id"`
+}
+
+type ReleaseFlatResponseObject interface {
+	VisitReleaseFlatResponse(w http.ResponseWriter) error
+}
+
+type ReleaseFlat200JSONResponse Flat
+
+func (response ReleaseFlat200JSONResponse) VisitReleaseFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReleaseFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response ReleaseFlat400JSONResponse) VisitReleaseFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ReleaseFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response ReleaseFlat401JSONResponse) VisitReleaseFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ReleaseFlat403JSONResponse struct{ N403JSONResponse }
+
+func (response ReleaseFlat403JSONResponse) VisitReleaseFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ReleaseFlat404JSONResponse Error
+
+func (response ReleaseFlat404JSONResponse) VisitReleaseFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReleaseFlat409JSONResponse Error
+
+func (response ReleaseFlat409JSONResponse) VisitReleaseFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReleaseFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response ReleaseFlat500JSONResponse) VisitReleaseFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreFlatRequestObject struct {
+	Id FlatId `json:"id"`
+}
+
+type RestoreFlatResponseObject interface {
+	VisitRestoreFlatResponse(w http.ResponseWriter) error
+}
+
+type RestoreFlat200JSONResponse Flat
+
+func (response RestoreFlat200JSONResponse) VisitRestoreFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response RestoreFlat400JSONResponse) VisitRestoreFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response RestoreFlat401JSONResponse) VisitRestoreFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreFlat403JSONResponse struct{ N403JSONResponse }
+
+func (response RestoreFlat403JSONResponse) VisitRestoreFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreFlat404JSONResponse Error
+
+func (response RestoreFlat404JSONResponse) VisitRestoreFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreFlat409JSONResponse Error
+
+func (response RestoreFlat409JSONResponse) VisitRestoreFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response RestoreFlat500JSONResponse) VisitRestoreFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ResubmitFlatRequestObject struct {
+	Id   FlatId `json:"id"`
+	Body *ResubmitFlatJSONRequestBody
+}
+
+type ResubmitFlatResponseObject interface {
+	VisitResubmitFlatResponse(w http.ResponseWriter) error
+}
+
+type ResubmitFlat200JSONResponse Flat
+
+func (response ResubmitFlat200JSONResponse) VisitResubmitFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ResubmitFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response ResubmitFlat400JSONResponse) VisitResubmitFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ResubmitFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response ResubmitFlat401JSONResponse) VisitResubmitFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ResubmitFlat403JSONResponse struct{ N403JSONResponse }
+
+func (response ResubmitFlat403JSONResponse) VisitResubmitFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ResubmitFlat404JSONResponse Error
+
+func (response ResubmitFlat404JSONResponse) VisitResubmitFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ResubmitFlat409JSONResponse Error
+
+func (response ResubmitFlat409JSONResponse) VisitResubmitFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ResubmitFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response ResubmitFlat500JSONResponse) VisitResubmitFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SearchFlatsRequestObject struct {
+	Params SearchFlatsParams
+}
+
+type SearchFlatsResponseObject interface {
+	VisitSearchFlatsResponse(w http.ResponseWriter) error
+}
+
+type SearchFlats200JSONResponse models.FlatSearchPage
+
+func (response SearchFlats200JSONResponse) VisitSearchFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchFlats400JSONResponse struct{ N400JSONResponse }
+
+func (response SearchFlats400JSONResponse) VisitSearchFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SearchFlats401JSONResponse struct{ N401JSONResponse }
+
+func (response SearchFlats401JSONResponse) VisitSearchFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SearchFlats500JSONResponse struct{ N5xxJSONResponse }
+
+func (response SearchFlats500JSONResponse) VisitSearchFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type HealthzRequestObject struct {
+}
+
+type HealthzResponseObject interface {
+	VisitHealthzResponse(w http.ResponseWriter) error
+}
+
+type Healthz200JSONResponse Health
+
+func (response Healthz200JSONResponse) VisitHealthzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateHouseRequestObject struct {
+	Body *CreateHouseJSONRequestBody
+}
+
+type CreateHouseResponseObject interface {
+	VisitCreateHouseResponse(w http.ResponseWriter) error
+}
+
+type CreateHouse200JSONResponse House
+
+func (response CreateHouse200JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateHouse400JSONResponse struct{ N400JSONResponse }
+
+func (response CreateHouse400JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateHouse401JSONResponse struct{ N401JSONResponse }
+
+func (response CreateHouse401JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateHouse403JSONResponse struct{ N403JSONResponse }
+
+func (response CreateHouse403JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateHouse500JSONResponse struct{ N5xxJSONResponse }
+
+func (response CreateHouse500JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteHouseRequestObject struct {
+	Id HouseId `json:"id"`
+}
+
+type DeleteHouseResponseObject interface {
+	VisitDeleteHouseResponse(w http.ResponseWriter) error
+}
+
+type DeleteHouse200JSONResponse House
+
+func (response DeleteHouse200JSONResponse) VisitDeleteHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteHouse400JSONResponse struct{ N400JSONResponse }
+
+func (response DeleteHouse400JSONResponse) VisitDeleteHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteHouse401JSONResponse struct{ N401JSONResponse }
+
+func (response DeleteHouse401JSONResponse) VisitDeleteHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteHouse403JSONResponse struct{ N403JSONResponse }
+
+func (response DeleteHouse403JSONResponse) VisitDeleteHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteHouse404JSONResponse Error
+
+func (response DeleteHouse404JSONResponse) VisitDeleteHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteHouse500JSONResponse struct{ N5xxJSONResponse }
+
+func (response DeleteHouse500JSONResponse) VisitDeleteHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetHouseFlatsRequestObject struct {
+	Id     HouseId `json:"id"`
+	Params GetHouseFlatsParams
+}
+
+type GetHouseFlatsResponseObject interface {
+	VisitGetHouseFlatsResponse(w http.ResponseWriter) error
+}
+
+type GetHouseFlats200JSONResponse models.HouseFlatsPage
+
+func (response GetHouseFlats200JSONResponse) VisitGetHouseFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetHouseFlats400JSONResponse struct{ N400JSONResponse }
+
+func (response GetHouseFlats400JSONResponse) VisitGetHouseFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetHouseFlats401JSONResponse struct{ N401JSONResponse }
+
+func (response GetHouseFlats401JSONResponse) VisitGetHouseFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetHouseFlats500JSONResponse struct{ N5xxJSONResponse }
+
+func (response GetHouseFlats500JSONResponse) VisitGetHouseFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EditHouseRequestObject struct {
+	Id   HouseId `json:"id"`
+	Body *EditHouseJSONRequestBody
+}
+
+type EditHouseResponseObject interface {
+	VisitEditHouseResponse(w http.ResponseWriter) error
+}
+
+type EditHouse200JSONResponse House
+
+func (response EditHouse200JSONResponse) VisitEditHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EditHouse400JSONResponse struct{ N400JSONResponse }
+
+func (response EditHouse400JSONResponse) VisitEditHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EditHouse401JSONResponse struct{ N401JSONResponse }
+
+func (response EditHouse401JSONResponse) VisitEditHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EditHouse403JSONResponse struct{ N403JSONResponse }
+
+func (response EditHouse403JSONResponse) VisitEditHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EditHouse404JSONResponse Error
+
+func (response EditHouse404JSONResponse) VisitEditHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EditHouse500JSONResponse struct{ N5xxJSONResponse }
+
+func (response EditHouse500JSONResponse) VisitEditHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetHouseRequestObject struct {
+	Id HouseId `json:"id"`
+}
+
+type GetHouseResponseObject interface {
+	VisitGetHouseResponse(w http.ResponseWriter) error
+}
+
+type GetHouse200JSONResponse House
+
+func (response GetHouse200JSONResponse) VisitGetHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetHouse400JSONResponse struct{ N400JSONResponse }
+
+func (response GetHouse400JSONResponse) VisitGetHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetHouse401JSONResponse struct{ N401JSONResponse }
+
+func (response GetHouse401JSONResponse) VisitGetHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetHouse404JSONResponse Error
+
+func (response GetHouse404JSONResponse) VisitGetHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetHouse500JSONResponse struct{ N5xxJSONResponse }
+
+func (response GetHouse500JSONResponse) VisitGetHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreHouseRequestObject struct {
+	Id HouseId `json:"id"`
+}
+
+type RestoreHouseResponseObject interface {
+	VisitRestoreHouseResponse(w http.ResponseWriter) error
+}
+
+type RestoreHouse200JSONResponse House
+
+func (response RestoreHouse200JSONResponse) VisitRestoreHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreHouse400JSONResponse struct{ N400JSONResponse }
+
+func (response RestoreHouse400JSONResponse) VisitRestoreHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreHouse401JSONResponse struct{ N401JSONResponse }
+
+func (response RestoreHouse401JSONResponse) VisitRestoreHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreHouse403JSONResponse struct{ N403JSONResponse }
+
+func (response RestoreHouse403JSONResponse) VisitRestoreHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreHouse404JSONResponse Error
+
+func (response RestoreHouse404JSONResponse) VisitRestoreHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreHouse500JSONResponse struct{ N5xxJSONResponse }
+
+func (response RestoreHouse500JSONResponse) VisitRestoreHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SubscribeRequestObject struct {
+	Id   HouseId `json:"id"`
+	Body *SubscribeJSONRequestBody
+}
+
+type SubscribeResponseObject interface {
+	VisitSubscribeResponse(w http.ResponseWriter) error
+}
+
+type Subscribe200Response struct {
+}
+
+func (response Subscribe200Response) VisitSubscribeResponse(w http.ResponseWriter) error {
+	w.WriteHeader(200)
+	return nil
+}
+
+type Subscribe400JSONResponse struct{ N400JSONResponse }
+
+func (response Subscribe400JSONResponse) VisitSubscribeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type Subscribe401JSONResponse struct{ N401JSONResponse }
+
+func (response Subscribe401JSONResponse) VisitSubscribeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type Subscribe404JSONResponse Error
+
+func (response Subscribe404JSONResponse) VisitSubscribeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type Subscribe500JSONResponse struct{ N5xxJSONResponse }
+
+func (response Subscribe500JSONResponse) VisitSubscribeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListHousesRequestObject struct {
+	Params ListHousesParams
+}
+
+type ListHousesResponseObject interface {
+	VisitListHousesResponse(w http.ResponseWriter) error
+}
+
+type ListHouses200JSONResponse models.HousePage
+
+func (response ListHouses200JSONResponse) VisitListHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListHouses400JSONResponse struct{ N400JSONResponse }
+
+func (response ListHouses400JSONResponse) VisitListHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListHouses401JSONResponse struct{ N401JSONResponse }
+
+func (response ListHouses401JSONResponse) VisitListHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListHouses500JSONResponse struct{ N5xxJSONResponse }
+
+func (response ListHouses500JSONResponse) VisitListHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type LoginRequestObject struct {
+	Body *LoginJSONRequestBody
+}
+
+type LoginResponseObject interface {
+	VisitLoginResponse(w http.ResponseWriter) error
+}
+
+type Login200JSONResponse models.AuthorizationToken
+
+func (response Login200JSONResponse) VisitLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type Login400JSONResponse Error
+
+func (response Login400JSONResponse) VisitLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type Login500JSONResponse struct{ N5xxJSONResponse }
+
+func (response Login500JSONResponse) VisitLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type LogoutRequestObject struct {
+	Body *LogoutJSONRequestBody
+}
+
+type LogoutResponseObject interface {
+	VisitLogoutResponse(w http.ResponseWriter) error
+}
+
+type Logout200Response struct {
+}
+
+func (response Logout200Response) VisitLogoutResponse(w http.ResponseWriter) error {
+	w.WriteHeader(200)
+	return nil
+}
+
+type Logout400JSONResponse struct{ N400JSONResponse }
+
+func (response Logout400JSONResponse) VisitLogoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type Logout401JSONResponse struct{ N401JSONResponse }
+
+func (response Logout401JSONResponse) VisitLogoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type Logout500JSONResponse struct{ N5xxJSONResponse }
+
+func (response Logout500JSONResponse) VisitLogoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetMyFlatsRequestObject struct {
+}
+
+type GetMyFlatsResponseObject interface {
+	VisitGetMyFlatsResponse(w http.ResponseWriter) error
+}
+
+type GetMyFlats200JSONResponse struct {
+	Flats []Flat `json:"flats"`
+}
+
+func (response GetMyFlats200JSONResponse) VisitGetMyFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetMyFlats401JSONResponse struct{ N401JSONResponse }
+
+func (response GetMyFlats401JSONResponse) VisitGetMyFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetMyFlats500JSONResponse struct{ N5xxJSONResponse }
+
+func (response GetMyFlats500JSONResponse) VisitGetMyFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetModerationQueueRequestObject struct {
+	Params GetModerationQueueParams
+}
+
+type GetModerationQueueResponseObject interface {
+	VisitGetModerationQueueResponse(w http.ResponseWriter) error
+}
+
+type GetModerationQueue200JSONResponse models.ModerationQueuePage
+
+func (response GetModerationQueue200JSONResponse) VisitGetModerationQueueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetModerationQueue400JSONResponse struct{ N400JSONResponse }
+
+func (response GetModerationQueue400JSONResponse) VisitGetModerationQueueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetModerationQueue401JSONResponse struct{ N401JSONResponse }
+
+func (response GetModerationQueue401JSONResponse) VisitGetModerationQueueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetModerationQueue403JSONResponse struct{ N403JSONResponse }
+
+func (response GetModerationQueue403JSONResponse) VisitGetModerationQueueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetModerationQueue500JSONResponse struct{ N5xxJSONResponse }
+
+func (response GetModerationQueue500JSONResponse) VisitGetModerationQueueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type TakeNextFlatRequestObject struct {
+	Params TakeNextFlatParams
+}
+
+type TakeNextFlatResponseObject interface {
+	VisitTakeNextFlatResponse(w http.ResponseWriter) error
+}
+
+type TakeNextFlat200JSONResponse Flat
+
+func (response TakeNextFlat200JSONResponse) VisitTakeNextFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type TakeNextFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response TakeNextFlat400JSONResponse) VisitTakeNextFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type TakeNextFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response TakeNextFlat401JSONResponse) VisitTakeNextFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type TakeNextFlat403JSONResponse struct{ N403JSONResponse }
+
+func (response TakeNextFlat403JSONResponse) VisitTakeNextFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type TakeNextFlat404JSONResponse Error
+
+func (response TakeNextFlat404JSONResponse) VisitTakeNextFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type TakeNextFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response TakeNextFlat500JSONResponse) VisitTakeNextFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ReadyzRequestObject struct {
+}
+
+type ReadyzResponseObject interface {
+	VisitReadyzResponse(w http.ResponseWriter) error
+}
+
+type Readyz200JSONResponse Health
+
+func (response Readyz200JSONResponse) VisitReadyzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type Readyz503JSONResponse struct{ N5xxJSONResponse }
+
+func (response Readyz503JSONResponse) VisitReadyzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RegisterRequestObject struct {
+	Body *RegisterJSONRequestBody
+}
+
+type RegisterResponseObject interface {
+	VisitRegisterResponse(w http.ResponseWriter) error
+}
+
+type Register200JSONResponse struct {
+	// UserId Идентификатор пользователя
+	UserId *UserId `json:"user_id,omitempty"`
+}
+
+func (response Register200JSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type Register400JSONResponse Error
+
+func (response Register400JSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type Register409JSONResponse Error
+
+func (response Register409JSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type Register500JSONResponse struct{ N5xxJSONResponse }
+
+func (response Register500JSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RefreshTokenRequestObject struct {
+	Body *RefreshTokenJSONRequestBody
+}
+
+type RefreshTokenResponseObject interface {
+	VisitRefreshTokenResponse(w http.ResponseWriter) error
+}
+
+type RefreshToken200JSONResponse models.AuthorizationToken
+
+func (response RefreshToken200JSONResponse) VisitRefreshTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RefreshToken400JSONResponse struct{ N400JSONResponse }
+
+func (response RefreshToken400JSONResponse) VisitRefreshTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RefreshToken401JSONResponse struct{ N401JSONResponse }
+
+func (response RefreshToken401JSONResponse) VisitRefreshTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RefreshToken500JSONResponse struct{ N5xxJSONResponse }
+
+func (response RefreshToken500JSONResponse) VisitRefreshTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("X-Request-Id", fmt.Sprint(response.Headers.XRequestId))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+
+	// (GET /decline-reasons)
+	ListDeclineReasons(ctx context.Context, request ListDeclineReasonsRequestObject) (ListDeclineReasonsResponseObject, error)
+
+	// (POST /decline-reasons)
+	SaveDeclineReason(ctx context.Context, request SaveDeclineReasonRequestObject) (SaveDeclineReasonResponseObject, error)
+
+	// (DELETE /decline-reasons/{code})
+	DeleteDeclineReason(ctx context.Context, request DeleteDeclineReasonRequestObject) (DeleteDeclineReasonResponseObject, error)
+
+	// (GET /dummyLogin)
+	DummyLogin(ctx context.Context, request DummyLoginRequestObject) (DummyLoginResponseObject, error)
+
+	// (POST /flat/create)
+	CreateFlat(ctx context.Context, request CreateFlatRequestObject) (CreateFlatResponseObject, error)
+
+	// (POST /flat/update)
+	UpdateFlat(ctx context.Context, request UpdateFlatRequestObject) (UpdateFlatResponseObject, error)
+
+	// (DELETE /flat/{id})
+	DeleteFlat(ctx context.Context, request DeleteFlatRequestObject) (DeleteFlatResponseObject, error)
+
+	// (PATCH /flat/{id})
+	EditFlat(ctx context.Context, request EditFlatRequestObject) (EditFlatResponseObject, error)
+
+	// (POST /flat/{id}/extend)
+	ExtendFlat(ctx context.Context, request ExtendFlatRequestObject) (ExtendFlatResponseObject, error)
+
+	// (GET /flat/{id}/history)
+	GetFlatHistory(ctx context.Context, request GetFlatHistoryRequestObject) (GetFlatHistoryResponseObject, error)
+
+	// (POST /flat/{id}/release)
+	ReleaseFlat(ctx context.Context, request ReleaseFlatRequestObject) (ReleaseFlatResponseObject, error)
+
+	// (POST /flat/{id}/restore)
+	RestoreFlat(ctx context.Context, request RestoreFlatRequestObject) (RestoreFlatResponseObject, error)
+
+	// (POST /flat/{id}/resubmit)
+	ResubmitFlat(ctx context.Context, request ResubmitFlatRequestObject) (ResubmitFlatResponseObject, error)
+
+	// (GET /flats/search)
+	SearchFlats(ctx context.Context, request SearchFlatsRequestObject) (SearchFlatsResponseObject, error)
+
+	// (GET /healthz)
+	Healthz(ctx context.Context, request HealthzRequestObject) (HealthzResponseObject, error)
+
+	// (POST /house/create)
+	CreateHouse(ctx context.Context, request CreateHouseRequestObject) (CreateHouseResponseObject, error)
+
+	// (DELETE /house/{id})
+	DeleteHouse(ctx context.Context, request DeleteHouseRequestObject) (DeleteHouseResponseObject, error)
+
+	// (GET /house/{id})
+	GetHouseFlats(ctx context.Context, request GetHouseFlatsRequestObject) (GetHouseFlatsResponseObject, error)
+
+	// (PATCH /house/{id})
+	EditHouse(ctx context.Context, request EditHouseRequestObject) (EditHouseResponseObject, error)
+
+	// (GET /house/{id}/info)
+	GetHouse(ctx context.Context, request GetHouseRequestObject) (GetHouseResponseObject, error)
+
+	// (POST /house/{id}/restore)
+	RestoreHouse(ctx context.Context, request RestoreHouseRequestObject) (RestoreHouseResponseObject, error)
+
+	// (POST /house/{id}/subscribe)
+	Subscribe(ctx context.Context, request SubscribeRequestObject) (SubscribeResponseObject, error)
+
+	// (GET /houses)
+	ListHouses(ctx context.Context, request ListHousesRequestObject) (ListHousesResponseObject, error)
+
+	// (POST /login)
+	Login(ctx context.Context, request LoginRequestObject) (LoginResponseObject, error)
+
+	// (POST /logout)
+	Logout(ctx context.Context, request LogoutRequestObject) (LogoutResponseObject, error)
+
+	// (GET /me/flats)
+	GetMyFlats(ctx context.Context, request GetMyFlatsRequestObject) (GetMyFlatsResponseObject, error)
+
+	// (GET /moderation/queue)
+	GetModerationQueue(ctx context.Context, request GetModerationQueueRequestObject) (GetModerationQueueResponseObject, error)
+
+	// (POST /moderation/queue/next)
+	TakeNextFlat(ctx context.Context, request TakeNextFlatRequestObject) (TakeNextFlatResponseObject, error)
+
+	// (GET /readyz)
+	Readyz(ctx context.Context, request ReadyzRequestObject) (ReadyzResponseObject, error)
+
+	// (POST /register)
+	Register(ctx context.Context, request RegisterRequestObject) (RegisterResponseObject, error)
+
+	// (POST /token/refresh)
+	RefreshToken(ctx context.Context, request RefreshTokenRequestObject) (RefreshTokenResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// ListDeclineReasons operation middleware
+func (sh *strictHandler) ListDeclineReasons(w http.ResponseWriter, r *http.Request) {
+	var request ListDeclineReasonsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListDeclineReasons(ctx, request.(ListDeclineReasonsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListDeclineReasons")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListDeclineReasonsResponseObject); ok {
+		if err := validResponse.VisitListDeclineReasonsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SaveDeclineReason operation middleware
+func (sh *strictHandler) SaveDeclineReason(w http.ResponseWriter, r *http.Request) {
+	var request SaveDeclineReasonRequestObject
+
+	var body SaveDeclineReasonJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SaveDeclineReason(ctx, request.(SaveDeclineReasonRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SaveDeclineReason")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SaveDeclineReasonResponseObject); ok {
+		if err := validResponse.VisitSaveDeclineReasonResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteDeclineReason operation middleware
+func (sh *strictHandler) DeleteDeclineReason(w http.ResponseWriter, r *http.Request, code DeclineReasonCode) {
+	var request DeleteDeclineReasonRequestObject
+
+	request.Code = code
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteDeclineReason(ctx, request.(DeleteDeclineReasonRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteDeclineReason")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteDeclineReasonResponseObject); ok {
+		if err := validResponse.VisitDeleteDeclineReasonResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DummyLogin operation middleware
+func (sh *strictHandler) DummyLogin(w http.ResponseWriter, r *http.Request, params DummyLoginParams) {
+	var request DummyLoginRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DummyLogin(ctx, request.(DummyLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DummyLogin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DummyLoginResponseObject); ok {
+		if err := validResponse.VisitDummyLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateFlat operation middleware
+func (sh *strictHandler) CreateFlat(w http.ResponseWriter, r *http.Request) {
+	var request CreateFlatRequestObject
+
+	var body CreateFlatJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateFlat(ctx, request.(CreateFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateFlatResponseObject); ok {
+		if err := validResponse.VisitCreateFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateFlat operation middleware
+func (sh *strictHandler) UpdateFlat(w http.ResponseWriter, r *http.Request) {
+	var request UpdateFlatRequestObject
+
+	var body UpdateFlatJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateFlat(ctx, request.(UpdateFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateFlatResponseObject); ok {
+		if err := validResponse.VisitUpdateFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteFlat operation middleware
+func (sh *strictHandler) DeleteFlat(w http.ResponseWriter, r *http.Request, id FlatId) {
+	var request DeleteFlatRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteFlat(ctx, request.(DeleteFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteFlatResponseObject); ok {
+		if err := validResponse.VisitDeleteFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// EditFlat operation middleware
+func (sh *strictHandler) EditFlat(w http.ResponseWriter, r *http.Request, id FlatId) {
+	var request EditFlatRequestObject
+
+	request.Id = id
+
+	var body EditFlatJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.EditFlat(ctx, request.(EditFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "EditFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(EditFlatResponseObject); ok {
+		if err := validResponse.VisitEditFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ExtendFlat operation middleware
+func (sh *strictHandler) ExtendFlat(w http.ResponseWriter, r *http.Request, id FlatId) {
+	var request ExtendFlatRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExtendFlat(ctx, request.(ExtendFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExtendFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExtendFlatResponseObject); ok {
+		if err := validResponse.VisitExtendFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetFlatHistory operation middleware
+func (sh *strictHandler) GetFlatHistory(w http.ResponseWriter, r *http.Request, id FlatId) {
+	var request GetFlatHistoryRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetFlatHistory(ctx, request.(GetFlatHistoryRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetFlatHistory")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetFlatHistoryResponseObject); ok {
+		if err := validResponse.VisitGetFlatHistoryResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ReleaseFlat operation middleware
+func (sh *strictHandler) ReleaseFlat(w http.ResponseWriter, r *http.Request, id FlatId) {
+	var request ReleaseFlatRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ReleaseFlat(ctx, request.(ReleaseFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ReleaseFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReleaseFlatResponseObject); ok {
+		if err := validResponse.VisitReleaseFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RestoreFlat operation middleware
+func (sh *strictHandler) RestoreFlat(w http.ResponseWriter, r *http.Request, id FlatId) {
+	var request RestoreFlatRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RestoreFlat(ctx, request.(RestoreFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RestoreFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RestoreFlatResponseObject); ok {
+		if err := validResponse.VisitRestoreFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ResubmitFlat operation middleware
+func (sh *strictHandler) ResubmitFlat(w http.ResponseWriter, r *http.Request, id FlatId) {
+	var request ResubmitFlatRequestObject
+
+	request.Id = id
+
+	var body ResubmitFlatJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ResubmitFlat(ctx, request.(ResubmitFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ResubmitFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ResubmitFlatResponseObject); ok {
+		if err := validResponse.VisitResubmitFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SearchFlats operation middleware
+func (sh *strictHandler) SearchFlats(w http.ResponseWriter, r *http.Request, params SearchFlatsParams) {
+	var request SearchFlatsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SearchFlats(ctx, request.(SearchFlatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SearchFlats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SearchFlatsResponseObject); ok {
+		if err := validResponse.VisitSearchFlatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Healthz operation middleware
+func (sh *strictHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	var request HealthzRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Healthz(ctx, request.(HealthzRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Healthz")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(HealthzResponseObject); ok {
+		if err := validResponse.VisitHealthzResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateHouse operation middleware
+func (sh *strictHandler) CreateHouse(w http.ResponseWriter, r *http.Request) {
+	var request CreateHouseRequestObject
+
+	var body CreateHouseJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateHouse(ctx, request.(CreateHouseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateHouse")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateHouseResponseObject); ok {
+		if err := validResponse.VisitCreateHouseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteHouse operation middleware
+func (sh *strictHandler) DeleteHouse(w http.ResponseWriter, r *http.Request, id HouseId) {
+	var request DeleteHouseRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteHouse(ctx, request.(DeleteHouseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteHouse")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteHouseResponseObject); ok {
+		if err := validResponse.VisitDeleteHouseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetHouseFlats operation middleware
+func (sh *strictHandler) GetHouseFlats(w http.ResponseWriter, r *http.Request, id HouseId, params GetHouseFlatsParams) {
+	var request GetHouseFlatsRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetHouseFlats(ctx, request.(GetHouseFlatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetHouseFlats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetHouseFlatsResponseObject); ok {
+		if err := validResponse.VisitGetHouseFlatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// EditHouse operation middleware
+func (sh *strictHandler) EditHouse(w http.ResponseWriter, r *http.Request, id HouseId) {
+	var request EditHouseRequestObject
+
+	request.Id = id
+
+	var body EditHouseJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.EditHouse(ctx, request.(EditHouseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "EditHouse")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(EditHouseResponseObject); ok {
+		if err := validResponse.VisitEditHouseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetHouse operation middleware
+func (sh *strictHandler) GetHouse(w http.ResponseWriter, r *http.Request, id HouseId) {
+	var request GetHouseRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetHouse(ctx, request.(GetHouseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetHouse")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetHouseResponseObject); ok {
+		if err := validResponse.VisitGetHouseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RestoreHouse operation middleware
+func (sh *strictHandler) RestoreHouse(w http.ResponseWriter, r *http.Request, id HouseId) {
+	var request RestoreHouseRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RestoreHouse(ctx, request.(RestoreHouseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RestoreHouse")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RestoreHouseResponseObject); ok {
+		if err := validResponse.VisitRestoreHouseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Subscribe operation middleware
+func (sh *strictHandler) Subscribe(w http.ResponseWriter, r *http.Request, id HouseId) {
+	var request SubscribeRequestObject
+
+	request.Id = id
+
+	var body SubscribeJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Subscribe(ctx, request.(SubscribeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Subscribe")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SubscribeResponseObject); ok {
+		if err := validResponse.VisitSubscribeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListHouses operation middleware
+func (sh *strictHandler) ListHouses(w http.ResponseWriter, r *http.Request, params ListHousesParams) {
+	var request ListHousesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListHouses(ctx, request.(ListHousesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListHouses")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListHousesResponseObject); ok {
+		if err := validResponse.VisitListHousesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Login operation middleware
+func (sh *strictHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var request LoginRequestObject
+
+	var body LoginJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Login(ctx, request.(LoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Login")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(LoginResponseObject); ok {
+		if err := validResponse.VisitLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Logout operation middleware
+func (sh *strictHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var request LogoutRequestObject
+
+	var body LogoutJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Logout(ctx, request.(LogoutRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Logout")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(LogoutResponseObject); ok {
+		if err := validResponse.VisitLogoutResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetMyFlats operation middleware
+func (sh *strictHandler) GetMyFlats(w http.ResponseWriter, r *http.Request) {
+	var request GetMyFlatsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetMyFlats(ctx, request.(GetMyFlatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetMyFlats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetMyFlatsResponseObject); ok {
+		if err := validResponse.VisitGetMyFlatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetModerationQueue operation middleware
+func (sh *strictHandler) GetModerationQueue(w http.ResponseWriter, r *http.Request, params GetModerationQueueParams) {
+	var request GetModerationQueueRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetModerationQueue(ctx, request.(GetModerationQueueRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetModerationQueue")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetModerationQueueResponseObject); ok {
+		if err := validResponse.VisitGetModerationQueueResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// TakeNextFlat operation middleware
+func (sh *strictHandler) TakeNextFlat(w http.ResponseWriter, r *http.Request, params TakeNextFlatParams) {
+	var request TakeNextFlatRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.TakeNextFlat(ctx, request.(TakeNextFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "TakeNextFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(TakeNextFlatResponseObject); ok {
+		if err := validResponse.VisitTakeNextFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Readyz operation middleware
+func (sh *strictHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	var request ReadyzRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Readyz(ctx, request.(ReadyzRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Readyz")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReadyzResponseObject); ok {
+		if err := validResponse.VisitReadyzResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Register operation middleware
+func (sh *strictHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var request RegisterRequestObject
+
+	var body RegisterJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Register(ctx, request.(RegisterRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Register")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RegisterResponseObject); ok {
+		if err := validResponse.VisitRegisterResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RefreshToken operation middleware
+func (sh *strictHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var request RefreshTokenRequestObject
+
+	var body RefreshTokenJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RefreshToken(ctx, request.(RefreshTokenRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RefreshToken")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RefreshTokenResponseObject); ok {
+		if err := validResponse.VisitRefreshTokenResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+xd7XMbx3n/V26u+dBOjwRIvVn8VMdJq8wkjSIrM01VlXMiluTFeNPdQSKtcoYkLMka",
+	"0mLiuhOP60hx3Gm/ghAhQSAJ/gu7/1HneXb3bvduDziQIEhJ/GJTwOH27Xn5Pa/7yF6oVeq1KqmGgT33",
+	"yF4mbon4+Oe/TN0i9xskCKd+UYJ/l0iw4Hv10KtV7Tmbfkv3aIcesk3aZV/QLu3RFtukfbZu0Te0RY/Y",
+	"Ou2zDdpyLLZB+7RNj2iL7tEW7bBNi21YPn/5vFeyaNtim7RD92nHon32Je3SXdqj3WmLvqQdtk47+MND",
+	"esi26FuL9ug+7YrB+/SAD/iK9uk+DtSnPUudPE6APWbr8A62AxNgG2zHduxgYZlUXFhcuFon9pwdhL5X",
+	"XbLX1tYcu+76boWEYjd+0yAN8jPygJRrdeLDJx5sw/0G8Vdtx666Ffh9KXpg0Msd/rYbtUZAflHKetcy",
+	"fD3vlbRX/cQni/ac/TeF+NgK/NugIN8XDXDT9xbIr9yVrBHq8P18xV3JPQS+MTmAVx0ygFcdeYA1x/ZJ",
+	"UK9VA4Lbf7lYhP8t1KohqYbwp1uvl70FF8ix8PughnPIN8bPfb/m8zESNP1n2qFt2kLy2kNq61gK6XUs",
+	"2kb62qMt2xnEK6bhxeMF7VmcxeXizIQW16JtzqS0S98gsyhstQcMyzZZkx6Nd3WXJrQ6sQAhiJ7SQ9q3",
+	"UBC1aNuih7Rl0T49QonSYk9olz0f5zqvrKxMYJ0vIvHYArkGa2nzFelruUVCf3Xq48WQC6vEW75GoXrA",
+	"dhyLPRUi9g0I1r4gjz4Qe4c9ox0L9++NRQ9Zk77GPWUbKPv3YaPZtibvTXLPq4ZkifgoNk6yxWvy3bjA",
+	"j0slnwSBYXF/oHuwILaBJE0PcGvIilupl2E69L/hOyAHtmOxJjA7ewJ66ppj0e9hEbQHjOFYM7NXZq5f",
+	"tZ2kAHfsn7khMYz8DZJey/p7i7blDmtjzxZnrk0Vr03NztyeuTZ3aXZu9qN/tR17seZX3BD0hxuSqdCr",
+	"EOOgZKHsVckt4gpaSoz+EtiaPaVdQepsEzVlnx6CrqRdtmPxlbF11NrrbMt27LoP+ir0uJxdqJXIMOLU",
+	"5vEJ/CBJpo/U7f5fHL0FDNjhWABm1hZqeJO2WRP+TsyNdtJbgErhfsPzScmeu8Pnqo98N/pN7d7vyUJo",
+	"O/bK1FJtSnxYqZVIOZjWN1J5ZMqr1Gs+8m7dDZftOdt94IW1n9Zq4YJbqReAkv2qWy7wF+GM0ruRPpnv",
+	"QGdwUSQOiG3lPqB4Kx/6terSPCpVODg3hNnYc/a/33GnPi9OXZ+/+2jGuXp57Sdm6lGwS2J+f6ItlPzA",
+	"92/ZM0Bz2sD0e/YVzn3HApzF1iMdWG2Uy+49eCj0G8Qw7M8rrldOD4kfW/QIQBvblrqIo8AEz4QkCP9h",
+	"CZ6fXqhVVG4h+G7ToCg+5x5lELf5dBLAU8DOQxB+8F+Qk/QQRMo+P6d93LQNBf4+oV3a5cfcp7sAZwGc",
+	"di26C8ukHZCpu2wr2upXIEtBUn0Zn7/242mLT45tWTMrKyvWFCiwXTwgHSmnMPesfFx9RqMtR0rHPijH",
+	"roV/7cXKEXfhT/HK+Zg9fvKcmekB7SQQdXRss8XijJPSAI696JFyySS1XyIp7KRWwkfiH8A+7eGK5EdC",
+	"+fVYE4nJYl+hTfAWBA1o+ifJ45m26H/hAWxbbFNQXw9+2LRiuYR7ApDTsb2QVII0KeEyjFbRAZ4i7DNt",
+	"wQbhWbcs2gVFA7Nu4uC0J6n/uW7/aMtXtbMANT3W1NhDioMUE1RIELhLJoL/P3iVkMebtEV7KJYtlc5h",
+	"i+mBNlC1UblHfKvSCELrHrHc0CoTNwit4lBBzTcrnlFKSq9FH7i+764Onv0LekS7sDmSJhUa1ybMnsI6",
+	"p/hij/CxfX3dpm2L7dITGL05BMgAmYCE0UUc0kpKBG2FSzOzjaXg6rWl+w+vXlttzMwuLpHG/YeloSci",
+	"d9fhMnGI2nTrHgGJOn1LmGTH0pnyLTiZfyy7oVEUqxCglYInJa5s5/1MDET7bAfRXSSyVLmGZ0Vbg4SA",
+	"JiSRNYVJwZqAty0xhZKJdvTpzZ8ATJVJSErzfI/ccvnXi/bcnSEvckNir911MqG+JUwf2BguXJJgA1aL",
+	"/35Mu7Sd0u4gvctuOF9tVAw7/2dUJh22nnqtSrIzoBUqXtWrwEuMGiLye+R1dzj28IeB3vizAN18tMnm",
+	"yUrd80kw7m3uo5Y8ZE+5iOLMvo8fd4XOSqtjtmWi1D49OAmt1qpWvFzTeYpva/7I4s7AVQ6Xsht0Xwi+",
+	"LsCfNn3DdmgbdTcKN33ZTWGYJ9AHez5t0T9yeuXgAjQoV4K0RzvpH4lJaPJxwSWXrpLi4tTV6+TK1OVF",
+	"96Mpd+ba9Sn3ymyp+NGlhStXLl9XAWWj4ZVS22Tg89rDKpFblo9sfhsQ/xclE+G8NELgbeE2fYPupzZq",
+	"uLepvTMdKccD+dxsju3XapVg2NO38CEwv0M3bAx9/FP+VFLn4NYqTk0JXPgUopcrQiafNYeq5IRGnBAP",
+	"I3HAICk3e+nylasKYXnV8Ople5jkg1nwzftk2a0awc+39A1H3ZF+01i+NdzCdxdids9DsY694BM31kbD",
+	"haM4wVGEMn82vSNV8nB+NKJz7Fq5pPwmH3vKX981+NtA+jQTvgrWFIAPYVrXon2VXQ+5HZg8CeSH/Lhl",
+	"uLfADF9PAj1MLCsP04lJRzsYjUDys6xG6Cdk3xvELYfLaSstJoNYJ9Q+G4qOxc9MZgqCDqPnr4/mUoLX",
+	"Yh/loIOQrsxj8dqksKJwpQ7HiCXV0TSY/uSDuSCcgvcadXCUjrBJq8QdOp3fwTNGDpDHKN6Tj8Y5pZyU",
+	"sOMQYX7FZHB6o0IaWR/ddIPgYc0vGeVVC+HpPtvm5uowLx79gXZoj8M56XuXNm8PJ5sSZTclkkmMHTmU",
+	"DeYLa07TzrRucRSLxUFLL5qWfoss+iRYvl37jJjE9V8lCo3Meb4HTek9gY8ORTQaUa8eeePOKBDlh+IB",
+	"FdhqG+fzmcyHOBXDNt2SEM7g1txHDdLhuouDb6AN2P9N5OS0zz0a+fIw8vg0kq6JkX+IEYkRJqEBeUfK",
+	"OmCwet2vPcA/FfNat17uqpui/CC1H1ln9oesE8DoZ7z/2u67jXDA1guMNBJ35mGWY9ot+vx0qaRP+jZ+",
+	"aCDrLj0aOEV5dGWPVEHwRQakfj7xx4Zd+50QxonB/1MESWg/EgxvuaFsEGizyNSDeBjMFbLQ8L1w9VOQ",
+	"8Fwj3yOuT/yPG+GyYQYGChGsrZAHazpKoBRpBxx4dD8hA5C9DoQ3eY+22GOrUGpUKqu/rC15VekPLpTh",
+	"X9MWfSE0rnBTO1o4XmQfaPa/yfilBw5ENHvgcmRbeHbPAa1KSR0dC3c5o4LfjdIAumyHPQZfRQdPHnzY",
+	"G1z/Ax3LyC5sMt/F+GyXw7DOo9VedbGGUN4Ludj/q5A+XBQK//Ze7LwV4hO2c5cvgjsEfuoufEaqJUuq",
+	"R9uxHxA/4Cc1M12cLiLWr5OqW/fsOfsSfoRRsWU86YKQJFMcEeNnS8TkdPxBwPg++uRj1J0nQmfjHLiI",
+	"Aklg/9ILQg1YB3YilWV2xFQWHVIqq4lCE7lBftrRnoA78u1p6GtIRcjaNztOaTHNLNqMAjwUJ4gMe/YS",
+	"PHulWBz+LGRiqPyPeFjl/Duq3AIcHLpLgfKxV6sGv66WV+274FCpBWEG4t8FtU67PKCj2mvNDNrhTE+7",
+	"kRUvfttPxDTQ5HwmFTdrsufwL/p22qI/Ivfs468FjsLENh5KONJzANpok0IuQos9o63Yl9bmATJxfCkq",
+	"/tR9QJJRchEY+WmttDq2PJcEderkKOyJEzHPiIMPzKhQ0wgFUEPqLeah3uJ7xRVrTkq+Fh6Bz2GNcwqY",
+	"pAae+ZHucnVl4Bf07kqa7GGM5rskvgfW4dqEZyM4kI6whzFAfkKQodTBh5IgBr53EpmgqBx5SDvFAT/D",
+	"NSR5QM0JvSNyHtGYi1IeRVKKTsXOcShSeGTumjlgMKU2+UbjP1I7e5qUeLl4eQJJcPpqeYYAxFnfcthN",
+	"W2fHFBHCy8YbP4o48TMuwzmE5B89QZG/YTIn9ciHgE2oCtg2yqO3cYiFv+wx7dPXdC96g9EG7aYpP16C",
+	"meATSb6NgPjziBaOS/aRRZJJ7ccES6E0BQeNzu3FtbVcXh0giprvfY7j3xZ24QlcPAbi/lGkrnwp1Dtt",
+	"gfs5ZU6icTKqAhqJJySlV2vICpzAwRtc4JY7LtMMjn7QfOGdlBcgKdyFdhW/0VCKFtCMXQY6zX6Cn4tI",
+	"0HGBSiLD53yGuk8vvJcwBQZE6iYeoJssLsT5DuPMRMAn6Y08bWw4CS37DS+uSWpXPoHrE5hAWkSIvC2e",
+	"WXAo2RDnKbFfRyRL0LZ0HHUiu2uPr0iNYNDOGLGCBhDAdaggAxScPHAxQHC+oLvCb7yvJl0mhef3Sd8P",
+	"mpAi4wyddbEQTbqvMnCASFnrGixX/JDush18WiQo8Khkz5jZnEScVtJYQCh/lIx5TltIc5AiiS4nesCL",
+	"bkQsij0WubB6yHtOagVr6t8axeIloqe9RJ9Kn7H1H1EOl6NbyJvptR+yLfCJ4Qbs4Sasq58nEnDMmSz4",
+	"ufBptpDmIhU3bdGvIT+GbRpPOvuNSkKRTJ/vCO9aRh4RUr9lzH5SE3dkBi3iyI7Mn3vNhwDfnWLB7eHg",
+	"rzDfNj0of2tEarwYRynn05V7KldJV/C/RcYZq4I/Ti7h8Ji8Y2QVCcq7CWKGd7bVlEIlYPY/+Po+fYVb",
+	"+gVnw+waCm7Mao4krG86tSTFUfI7Tp44lBWT/5AQB1CWph0mjTrOmx8gCRAyPAGTQSuiAjGhvt4mOD6V",
+	"ryWgSS+9lJahJgMzj2K5+yojEHRm/g9EOY+80mA/4MsouYR7uLuGrFAVpKGa3MfAEVZJsCeqWmJN4RLn",
+	"znS2AUqdPU+90zFsFdTI7LPndBcEaIYTUEiT4b4/FFTHc4FIUXliB8ixxEyKj47UA5KiRj2RCzmTkDOS",
+	"kaUZMnEbA0OfC8v5ckafwJ9si0eyE1kirUSWSFe1tIxZNzFrsm189YHeuYE952g3EcCOomBgrcRYH8vA",
+	"MCKuAW7uC+ulKVUC1/h9scczM/6VhauzPE6paEQzU0Lj+Bh5YDvqpFA6CVDcM8J0AbiRuADfRZoEj4Hr",
+	"iUMR3wBsiYe2acb0KVn285IXTl6SHQ+ou6WSB1+55ZsKZF90ywFxzsBNd6o+t5xwFo7vpJB27VzoFp1N",
+	"LxDr2SHWsfvXcoLYSXrcAIsWyEpIqqUBbreXoqRdYsh0wVaq3Aq1Z1s4b9ADklnH9I2aySW9cdl5XKYM",
+	"M/7Aa64KDJNLi3tc8vsPXf+Y3Iu4ZHZPWskXIubciJhoMkbkFGGcd9r0LSx7QVjzV7MzAL5Vk1h0xNmn",
+	"bZOg6bNNaZW1ubTRyo96ydJLniumC55WRs4oQvwEiDeIu5SI+SeCgPKGWO27ImZ0/KicVa58ylTp0rCU",
+	"SjlArpTKwYRxIcdOL80nH5DwCfS8GBTA+xq56zDq8pFyQQnIAP6mLMjgJO1QxQo9Cyxxi6/6A/SD6f6D",
+	"2A/Wj/qy7LHtC7a8gBeThBc+AY1yfCHE4/OKe7Jl4Hgc4sPkeLbBZa8earvg8gyPNz00+7snIwFEktLA",
+	"TjZj9b+fgGsb9ypeOIBtv4VoL29XGhWGpPJRmobIlkyn0ZMtuAtZe12Hdo7tpxhuIRiyOhPpDl1edpqB",
+	"bQzr51E+nMwTmb7UNUUt9NpWR/FeGRvtaH3r1C5rNz++/ckNKz43k2jEc3wPfekXfu5xahKV/fbjHqgm",
+	"3rvQLecMQSbF7iQtzaAQENdfWM52H72U7QHTHcBQUGNf6MdR3TR7jLKd19lis8GnMn/RWOkNzqNU6DQj",
+	"hmvQu4kAqky7dKKiFVN6YhsSMdoiF4PHG7TXmKrBdBVvGVuqKS1Ok49z2GJeZbogEo8EmD/IVxVz/Nb3",
+	"ztib9ZtfiPJ7pBlGwnzgC92V47wwQeLfY21XF8hXJhsDUb7K0SPANDXo2zLSUmUzGMPEWrQHTtfxTs1d",
+	"OcbUTnADRbonrEhR3rYA5PH+6UrbH0f0BbB4TRp2OYeH6CuejCBa75lmFHfPGWU+L9FftYPjJ+XcnFUl",
+	"D0kQWlOWaOQOrRb3ufQGELxFO47FGcYNFqwpngTPmR1ZXdp3kPMRJ8PwX5RI/BMspNyi7dTDGWsNAKqo",
+	"Cy2RRbdRDu05m09Z6aERfRBNNPobpqA4jNVtMo1a9ipexrCz0CXDXRHJDcXi4FQHwzl8x5psHctm1y0Z",
+	"X4jr0WUPH749T3hMlG3p17VwsS+jpK9FBnOkc0U/zIwdXWj4QW0wMY83IIAqeKRwgKl9cZWshPNi7nNj",
+	"2NS+sQ0dh5OpyE/iBTla6fNFj9C8DdXhTfekrduMabQxDhSXrhga6Z0WWp4AxlvGZnWfD4B3amNzOODX",
+	"CFn6MlU2apkCX3fkWSu9jV+I2oCnPONNzTnTy4zheWy7shk9uYvtqlvKtTei/qXHvmJfqt3Y+QzZTiZm",
+	"uiEWeorWGB8iozsJD5Z2DevMLm/Fqsdj1Ldqvb6EzpzOKFOVneHGU8Yyeo/B4zXoO3YLvbPqnjfZEg4+",
+	"4dGqRgWlfNBdRDjDHadiwFDMCRDvQDzREZcaoDVMD+AXpgbUeElDN495KexFcTOY1kKnh+29Il/4Ee+e",
+	"JVOf0xFDU06cufZA8sGpuhnji+PungmHCB9+qujgQy45yCi+nliZwSgdsrK9VEoLkxSQw8mDsSBb1RxG",
+	"ZarAxB+GzyrfjM1pW7xPjH7RDMzCqy6UGyWwIrEzsClvC3lxkDfrFGSLMyRMLVKPXxvroPV9gzZoUIXL",
+	"NoVx3jFu26CzyzA303tnMKpF8EQglnu1Wpm41XfF0Xb+nZUmOQIOnUFOHO3qwlfcc3MUu5C4EGJbKcoa",
+	"3Y8TFZzEnhzlI71VitmFk1jfX9Di4qHKpOXPL8HA2xclesmSGBzoZLrAs7xzad/Rhbvowl00RndRrGpO",
+	"xV2UqJDX+qaxLbMmEQjjHfYjDSj2VHIolBYyik/fEbGKiBb0aAW/SzB5HaQSChhHbec3pkY8hr6oSiml",
+	"saDxLMyj99Zpk5eZz3uKxlBDU6tAvDArFbPyvKTNxV6hguwontvIhN48XyASPIhTf/u6XWmUPmrDddiT",
+	"HDZYpm31vrtsvk1uMhrqE1GtZ978bhIxopj+R8723ouEnJbjjT1U1NxmjhC6tK0ocUPlmXRmtuW9u4Mc",
+	"rnHCzUFWTvmH5NHMSCa/UDmqyjk8J37MBN8FjXuwjHuDOA+7FaJ7AhFr3O0tddEFgmZQIKI9G7Bq3ImE",
+	"NWHVgnf24/Yl/SihJdVskEccYmMmHWz8NFrAu4ePibw1fiDJ4UNJ25T/NJ9tKraoLloOTiLYOKTBW6RU",
+	"o4zhI4Vseu9xW9nTh6BDFW7eO2KipNLodvpUuywRq1DXglWdilOyx8FqxkVRwkedvlzmBp9priTU3MmA",
+	"Y86ZHHOeo+HyNcXHL/MT21qhK3useLq0pqZtfskhl7L8oR2M60JByQbbzusj5r18S/OBV0WXb7yq6F4u",
+	"eGAq9CrEPptcvnPhIY1ZK5eLVICqd95HKpY9gpP0VPyjZsH1jqfQleUFG2PBZfrNG5m3LaCwEF8eaK2M",
+	"IRRpIe6w/ja+4ilLsLf+TpTryRs0dzJv3Ivq+iZzFUgccoeXwBdtYdvJWyoHThR+xLMWlceVRWY24+vT",
+	"PVFQikFgHvShfTQ4d8XtoAlFKK4nmTzazNP3OL46u67coDow+imfS0qR6AX55AiMfN6y5pL316nXmQ4L",
+	"W6u3sIJK+IDuc5lAt+I275rLE9q4B7QVZ13H5rnkavwcmaWQLd8Swm17PNfOlGtLtcag2umvAfLxEhxx",
+	"+4BokgnBJ5ET/SbOFlFkqGOxDQ06oueGbUUaBI0TbLeEsnTDsZQ+7lrsWhC3fplsSnLBQsYluk7ATvkY",
+	"RPzmFp/tKYV9sm5Xlj32UW/APr/zyf8VUogyBsyWZjJlDGm4x6+DHKKDtepPvc84ewx9gHiCmDkTT7W5",
+	"DVGNX63KdLFzljyRLzVhaOkv28rc2PNIRpH3snC/QRokPzlldjg21c86hv5QGb11Eqa3FL5QRN1yMguT",
+	"RWc7oFCQ0zHZitf04oq+gxidGhDxcItPl9gSeypGpQiZiFt0ICtJZbrovg3aMrJHdCC/wfNIuWdM1BA/",
+	"UsBfxdd85XteDfXn+wVm2f3Kq474A3fF/nD8FRcZXUMgQYLWT8Vt8WJYucSHXDOTlP4FILaBAWLecnA7",
+	"s++9EMBN/FMI4IxGQyAjlWIWvKoeYxTieq4d9oz/FD+00DiAhkGbpg5B8gqCCHpjKJB7a9kmnylPI5O3",
+	"T4nYF5bVyG5tXdrRFyXe5shKRWykT3txoDslwm+7n5F/JisZXX3eeeF9XnqrIR1e9MExyjh528PZtRj0",
+	"iVtaHaUw+ZWwztqA0mRdHt5zJpt3cKmjGM9sa254kbGaiXVo6Dx8i0/07AuL4x3gh3bphG4Onyx5QUj8",
+	"AcJ8JN/2X/QOHTJRS6tWzrJ60psu5nY2btfRXamOcjV1/huoTdkEyujqS98XnyyuKL9bey2PcZ30erJ1",
+	"EymeH3/n5DqOvTTy23bi9g0eTkpcujEeXyr6CgvCczjkLloEXwa/ZqTDuXkuAB/CRvWpPt7d9oM06Y0u",
+	"UktLkOvGNzTxu2zf4tdtVdaBN8CxVHeY0vdS3I+UFGsyO7if8AOLsql4OlHSY+xhiBzicR2WQToqvtXz",
+	"4d1V5Zj+prtn4Pq9CCuN10LWneT6xaDnyE2elkAIWP0H0rZq+GV7zl4Ow/pcoVCuLbjl5VoQzn1U/Kho",
+	"K5DVhIO0ZrWy0YNjDG4LiWJBmjQ2lG3FHiYxtTVn+BhRqXf6Gm3pII0q2VkzHiJy3+YYZKQaZvH+JJ5f",
+	"u7v2/wMAOvxog9qxAAA=",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,5 @@
+// Package api is generated from api.yaml: the types, the strict server
+// interface implemented by the handlers and the embedded spec.
+package api
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen --config=oapi-codegen.yaml ../../api.yaml
@@ -0,0 +1,141 @@
+// Package apierror holds the application errors returned by the handlers and
+// writes them in the error format of api.yaml.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// Codes are part of the API, an existing code must never change its meaning.
+const (
+	CodeInternal           = 1000
+	CodeInvalidRequest     = 1001
+	CodeUnauthorized       = 1002
+	CodeNotModerator       = 1003
+	CodeInvalidCredentials = 1004
+	CodeInvalidRefresh     = 1005
+	CodeRouteNotFound      = 1006
+	CodeMethodNotAllowed   = 1007
+	CodeUnavailable        = 1008
+	CodeEmailTaken         = 1009
+
+	CodeFlatNotFound            = 2001
+	CodeHouseNotFound           = 2002
+	CodeFlatLocked              = 2003
+	CodeNotFlatOwner            = 2004
+	CodeInvalidFlatStatus       = 2005
+	CodeInvalidStatusTransition = 2006
+	CodeFlatNumberTaken         = 2007
+	CodeHouseDeleted            = 2008
+	CodeInvalidDeclineReason    = 2009
+	CodeDeclineReasonNotFound   = 2010
+	CodeModerationQueueEmpty    = 2011
+	CodeFlatHistoryNotAvailable = 2012
+)
+
+// FieldError points at the request field that failed validation. Field is
+// the query or path parameter name, or the dotted path in the JSON body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an error with the status and code of the response. Err is the
+// cause, it is logged but never sent to the client.
+type Error struct {
+	Status  int
+	Code    int
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + `: ` + e.Err.Error()
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func New(status int, code int, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+var (
+	ErrRouteNotFound           = New(http.StatusNotFound, CodeRouteNotFound, `Route not found`)
+	ErrMethodNotAllowed        = New(http.StatusMethodNotAllowed, CodeMethodNotAllowed, `Method not allowed`)
+	ErrUnauthorized            = New(http.StatusUnauthorized, CodeUnauthorized, `Invalid authorization token`)
+	ErrNotModerator            = New(http.StatusForbidden, CodeNotModerator, `You are not a moderator`)
+	ErrInvalidCredentials      = New(http.StatusBadRequest, CodeInvalidCredentials, `Invalid credentials`)
+	ErrInvalidRefreshToken     = New(http.StatusUnauthorized, CodeInvalidRefresh, `Invalid refresh token`)
+	ErrEmailTaken              = New(http.StatusConflict, CodeEmailTaken, `A user with this email already exists`)
+	ErrFlatNotFound            = New(http.StatusNotFound, CodeFlatNotFound, `Flat not found`)
+	ErrHouseNotFound           = New(http.StatusNotFound, CodeHouseNotFound, `House not found`)
+	ErrFlatLocked              = New(http.StatusConflict, CodeFlatLocked, `This apartment is being moderated by another moderator`)
+	ErrNotFlatOwner            = New(http.StatusForbidden, CodeNotFlatOwner, `Only the owner can change this flat`)
+	ErrInvalidFlatStatus       = New(http.StatusBadRequest, CodeInvalidFlatStatus, `Invalid flat status`)
+	ErrInvalidStatusTransition = New(http.StatusConflict, CodeInvalidStatusTransition, `Flat status can not be changed this way`)
+	ErrFlatNumberTaken         = New(http.StatusConflict, CodeFlatNumberTaken, `A flat with this number already exists in the house`)
+	ErrHouseDeleted            = New(http.StatusConflict, CodeHouseDeleted, `The house is archived`)
+	ErrInvalidDeclineReason    = New(http.StatusBadRequest, CodeInvalidDeclineReason, `Invalid decline reason`)
+	ErrDeclineReasonNotFound   = New(http.StatusNotFound, CodeDeclineReasonNotFound, `Decline reason not found`)
+	ErrModerationQueueEmpty    = New(http.StatusNotFound, CodeModerationQueueEmpty, `No flats awaiting moderation`)
+	ErrFlatHistoryForbidden    = New(http.StatusForbidden, CodeFlatHistoryNotAvailable, `Only moderators and the owner can see the flat history`)
+)
+
+// BadRequest reports invalid input, the message is sent to the client as is.
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, CodeInvalidRequest, message)
+}
+
+// Invalid reports a request that does not match api.yaml, field by field.
+func Invalid(fields []FieldError) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeInvalidRequest, Message: `Invalid request`, Fields: fields}
+}
+
+// Internal hides the cause behind a generic message.
+func Internal(err error) *Error {
+	return &Error{Status: http.StatusInternalServerError, Code: CodeInternal, Message: `Internal server error`, Err: err}
+}
+
+// Unavailable reports a dependency that does not answer, the client may retry.
+func Unavailable(err error) *Error {
+	return &Error{Status: http.StatusServiceUnavailable, Code: CodeUnavailable, Message: `Service unavailable`, Err: err}
+}
+
+// Response is the error body of api.yaml.
+type Response struct {
+	Message   string       `json:"message"`
+	RequestId string       `json:"request_id,omitempty"`
+	Code      int          `json:"code"`
+	Fields    []FieldError `json:"fields,omitempty"`
+}
+
+// Write sends err as an error response. Errors that are not an *Error are
+// treated as internal. Server errors are logged with the request id and ask
+// the client to retry.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	var appErr *Error
+	if !errors.As(err, &appErr) {
+		appErr = Internal(err)
+	}
+
+	requestId, _ := r.Context().Value(`requestId`).(string)
+
+	if appErr.Status >= http.StatusInternalServerError {
+		slog.Error(`Request failed`, `request_id`, requestId, `method`, r.Method, `path`, r.URL.Path, `error`, err)
+		w.Header().Set(`Retry-After`, `3`)
+	}
+
+	w.Header().Set(`Content-Type`, `application/json`)
+	w.WriteHeader(appErr.Status)
+	json.NewEncoder(w).Encode(Response{Message: appErr.Message, RequestId: requestId, Code: appErr.Code, Fields: appErr.Fields})
+}
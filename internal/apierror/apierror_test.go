@@ -0,0 +1,67 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		err              error
+		expectedStatus   int
+		expectedResponse Response
+		expectRetry      bool
+	}{
+		// Тест 1: Ошибка приложения отдаётся со своим статусом и кодом
+		{
+			err:              ErrFlatNotFound,
+			expectedStatus:   http.StatusNotFound,
+			expectedResponse: Response{Message: `Flat not found`, RequestId: `test-request`, Code: CodeFlatNotFound},
+		},
+		// Тест 2: Обёрнутая ошибка приложения распознаётся
+		{
+			err:              fmt.Errorf(`update flat: %w`, ErrFlatNumberTaken),
+			expectedStatus:   http.StatusConflict,
+			expectedResponse: Response{Message: ErrFlatNumberTaken.Message, RequestId: `test-request`, Code: CodeFlatNumberTaken},
+		},
+		// Тест 3: Текст произвольной ошибки не попадает в ответ
+		{
+			err:              errors.New(`pq: relation "flat" does not exist`),
+			expectedStatus:   http.StatusInternalServerError,
+			expectedResponse: Response{Message: `Internal server error`, RequestId: `test-request`, Code: CodeInternal},
+			expectRetry:      true,
+		},
+		// Тест 4: Сообщение о неверном запросе отдаётся как есть
+		{
+			err:              BadRequest(`Invalid limit`),
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: Response{Message: `Invalid limit`, RequestId: `test-request`, Code: CodeInvalidRequest},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, `/`, nil)
+			req = req.WithContext(context.WithValue(req.Context(), `requestId`, `test-request`))
+			rr := httptest.NewRecorder()
+
+			Write(rr, req, tc.err)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			assert.Equal(t, `application/json`, rr.Header().Get(`Content-Type`))
+			assert.Equal(t, tc.expectRetry, rr.Header().Get(`Retry-After`) != ``)
+
+			var response Response
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResponse, response)
+		})
+	}
+}
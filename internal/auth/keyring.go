@@ -0,0 +1,270 @@
+package auth
+
+import (
+	"avitoBootcamp/internal/config"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// minHMACSecretLength is the shortest HS256 secret accepted, from the key
+// set file and from JWT_SECRET alike.
+const minHMACSecretLength = 32
+
+var (
+	ErrUnknownKey    = errors.New("unknown signing key")
+	ErrInvalidMethod = errors.New("unexpected signing method")
+	ErrShortSecret   = fmt.Errorf("HS256 secret must be at least %d bytes", minHMACSecretLength)
+)
+
+// Key is a single JWT key identified by its kid. A key without a private
+// part can only verify tokens, which is how keys of other services are added.
+type Key struct {
+	Id        string
+	Method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+}
+
+func NewHMACKey(id string, secret []byte) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}
+}
+
+func NewRSAKey(id string, privateKey *rsa.PrivateKey) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodRS256, signKey: privateKey, verifyKey: &privateKey.PublicKey}
+}
+
+func NewRSAPublicKey(id string, publicKey *rsa.PublicKey) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodRS256, verifyKey: publicKey}
+}
+
+func NewEdDSAKey(id string, privateKey ed25519.PrivateKey) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodEdDSA, signKey: privateKey, verifyKey: privateKey.Public()}
+}
+
+func NewEdDSAPublicKey(id string, publicKey ed25519.PublicKey) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodEdDSA, verifyKey: publicKey}
+}
+
+func (key *Key) CanSign() bool {
+	return key.signKey != nil
+}
+
+// Keyring signs tokens with the current key and verifies them with any known
+// key, so tokens issued before a rotation stay valid until they expire.
+type Keyring struct {
+	current *Key
+	keys    map[string]*Key
+}
+
+func NewKeyring(current *Key, previous ...*Key) (*Keyring, error) {
+	if current == nil || !current.CanSign() {
+		return nil, errors.New("current key must be able to sign tokens")
+	}
+
+	keyring := &Keyring{current: current, keys: map[string]*Key{current.Id: current}}
+
+	for _, key := range previous {
+		if _, ok := keyring.keys[key.Id]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.Id)
+		}
+
+		keyring.keys[key.Id] = key
+	}
+
+	return keyring, nil
+}
+
+func (keyring *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(keyring.current.Method, claims)
+	token.Header[`kid`] = keyring.current.Id
+
+	return token.SignedString(keyring.current.signKey)
+}
+
+func (keyring *Keyring) Parse(tokenStr string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenStr, claims, keyring.keyFunc)
+}
+
+func (keyring *Keyring) keyFunc(token *jwt.Token) (interface{}, error) {
+	key := keyring.current
+
+	if kid, ok := token.Header[`kid`].(string); ok {
+		if key, ok = keyring.keys[kid]; !ok {
+			return nil, ErrUnknownKey
+		}
+	}
+
+	// The algorithm is bound to the key, never taken from the token, otherwise
+	// a public RSA key could be used as an HMAC secret.
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrInvalidMethod
+	}
+
+	return key.verifyKey, nil
+}
+
+// Load builds the keyring from the key set file or the HS256 secret of the
+// config. Without either a random key is generated, so tokens do not survive
+// a restart; this is only meant for local runs.
+func Load(cfg config.JWT) (*Keyring, error) {
+	if cfg.KeysFile != `` {
+		return LoadFile(cfg.KeysFile)
+	}
+
+	if cfg.Secret != `` {
+		if len(cfg.Secret) < minHMACSecretLength {
+			return nil, fmt.Errorf("JWT_SECRET: %w", ErrShortSecret)
+		}
+
+		return NewKeyring(NewHMACKey(`default`, []byte(cfg.Secret)))
+	}
+
+	slog.Warn("Neither JWT_KEYS_FILE nor JWT_SECRET is set, using a random signing key")
+
+	return NewRandomKeyring()
+}
+
+func NewRandomKeyring() (*Keyring, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return NewKeyring(NewHMACKey(`random`, secret))
+}
+
+type keyFile struct {
+	Current string          `json:"current"`
+	Keys    []keyFileRecord `json:"keys"`
+}
+
+type keyFileRecord struct {
+	Id             string `json:"kid"`
+	Alg            string `json:"alg"`
+	Secret         string `json:"secret"`
+	PrivateKeyFile string `json:"private_key_file"`
+	PublicKeyFile  string `json:"public_key_file"`
+}
+
+// LoadFile reads a JSON key set:
+//
+//	{
+//	  "current": "2024-09",
+//	  "keys": [
+//	    {"kid": "2024-09", "alg": "EdDSA", "private_key_file": "/run/secrets/jwt-2024-09.pem"},
+//	    {"kid": "2024-08", "alg": "HS256", "secret": "<base64>"}
+//	  ]
+//	}
+//
+// Keys other than the current one are used only for verification.
+func LoadFile(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	var current *Key
+	var previous []*Key
+
+	for _, record := range file.Keys {
+		key, err := record.key()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", record.Id, err)
+		}
+
+		if key.Id == file.Current {
+			current = key
+		} else {
+			previous = append(previous, key)
+		}
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("current key %q is not in %s", file.Current, path)
+	}
+
+	return NewKeyring(current, previous...)
+}
+
+func (record keyFileRecord) key() (*Key, error) {
+	if record.Id == `` {
+		return nil, errors.New("kid is required")
+	}
+
+	switch record.Alg {
+	case jwt.SigningMethodHS256.Alg():
+		secret, err := base64.StdEncoding.DecodeString(record.Secret)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(secret) < minHMACSecretLength {
+			return nil, ErrShortSecret
+		}
+
+		return NewHMACKey(record.Id, secret), nil
+	case jwt.SigningMethodRS256.Alg():
+		if record.PrivateKeyFile != `` {
+			privateKey, err := readPEM(record.PrivateKeyFile, jwt.ParseRSAPrivateKeyFromPEM)
+			if err != nil {
+				return nil, err
+			}
+
+			return NewRSAKey(record.Id, privateKey), nil
+		}
+
+		publicKey, err := readPEM(record.PublicKeyFile, jwt.ParseRSAPublicKeyFromPEM)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewRSAPublicKey(record.Id, publicKey), nil
+	case jwt.SigningMethodEdDSA.Alg():
+		if record.PrivateKeyFile != `` {
+			privateKey, err := readPEM(record.PrivateKeyFile, jwt.ParseEdPrivateKeyFromPEM)
+			if err != nil {
+				return nil, err
+			}
+
+			return NewEdDSAKey(record.Id, privateKey.(ed25519.PrivateKey)), nil
+		}
+
+		publicKey, err := readPEM(record.PublicKeyFile, jwt.ParseEdPublicKeyFromPEM)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewEdDSAPublicKey(record.Id, publicKey.(ed25519.PublicKey)), nil
+	default:
+		return nil, fmt.Errorf("unsupported alg %q", record.Alg)
+	}
+}
+
+func readPEM[T any](path string, parse func([]byte) (T, error)) (T, error) {
+	var empty T
+
+	if path == `` {
+		return empty, errors.New("key file is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return empty, err
+	}
+
+	return parse(data)
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"avitoBootcamp/internal/config"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClaims() *jwt.RegisteredClaims {
+	return &jwt.RegisteredClaims{
+		Subject:   `user`,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	oldKey := NewHMACKey(`old`, []byte(`old-secret-old-secret-old-secret!`))
+	oldKeyring, err := NewKeyring(oldKey)
+	assert.NoError(t, err)
+
+	oldToken, err := oldKeyring.Sign(newClaims())
+	assert.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	keyring, err := NewKeyring(NewRSAKey(`new`, rsaKey), oldKey)
+	assert.NoError(t, err)
+
+	newToken, err := keyring.Sign(newClaims())
+	assert.NoError(t, err)
+
+	// Тест 1: Токен, выпущенный до ротации, остается валидным
+	token, err := keyring.Parse(oldToken, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+	assert.True(t, token.Valid)
+
+	// Тест 2: Новый токен подписан текущим ключом и содержит kid
+	token, err = keyring.Parse(newToken, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+	assert.Equal(t, `new`, token.Header[`kid`])
+	assert.Equal(t, jwt.SigningMethodRS256.Alg(), token.Method.Alg())
+
+	// Тест 3: Старый набор ключей не знает новый kid
+	_, err = oldKeyring.Parse(newToken, &jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyringPublicKeyOnly(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	issuer, err := NewKeyring(NewEdDSAKey(`ed`, privateKey))
+	assert.NoError(t, err)
+
+	tokenStr, err := issuer.Sign(newClaims())
+	assert.NoError(t, err)
+
+	// Другой сервис проверяет токен, зная только публичный ключ
+	verifier, err := NewKeyring(NewHMACKey(`own`, []byte(`own-secret-own-secret-own-secret!`)), NewEdDSAPublicKey(`ed`, publicKey))
+	assert.NoError(t, err)
+
+	token, err := verifier.Parse(tokenStr, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+	assert.True(t, token.Valid)
+
+	// Ключ без приватной части не может быть текущим
+	_, err = NewKeyring(NewEdDSAPublicKey(`ed`, publicKey))
+	assert.Error(t, err)
+}
+
+func TestKeyringRejectsAlgorithmConfusion(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	keyring, err := NewKeyring(NewRSAKey(`rsa`, rsaKey))
+	assert.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims())
+	token.Header[`kid`] = `rsa`
+	tokenStr, err := token.SignedString([]byte(`guessed-secret`))
+	assert.NoError(t, err)
+
+	_, err = keyring.Parse(tokenStr, &jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, ErrInvalidMethod)
+}
+
+func TestLoadSecret(t *testing.T) {
+	// Тест 1: Короткий секрет из переменной окружения отклоняется
+	_, err := Load(config.JWT{Secret: `x`})
+	assert.ErrorIs(t, err, ErrShortSecret)
+
+	// Тест 2: Секрет достаточной длины принимается
+	keyring, err := Load(config.JWT{Secret: `test-secret-test-secret-test-secret`})
+	assert.NoError(t, err)
+
+	_, err = keyring.Sign(newClaims())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,19 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// HashPassword returns the hash stored for a password. Registration and the
+// admin CLI both hash passwords here.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return ``, err
+	}
+
+	return string(hash), nil
+}
+
+// CheckPassword reports whether the password matches the stored hash.
+func CheckPassword(hash string, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// NewTokenId returns a random identifier for the jti claim, used to revoke
+// a single access token.
+func NewTokenId() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ``, err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// NewRefreshToken returns an opaque refresh token for the client and its hash.
+// Only the hash is stored, so a database leak does not leak usable tokens.
+func NewRefreshToken() (string, string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return ``, ``, err
+	}
+
+	tokenStr := base64.RawURLEncoding.EncodeToString(token)
+
+	return tokenStr, HashRefreshToken(tokenStr), nil
+}
+
+func HashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
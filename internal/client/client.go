@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// WithBearerToken authorizes a request with an access token.
+func WithBearerToken(token string) RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		req.Header.Set(`Authorization`, `Bearer `+token)
+		return nil
+	}
+}
+
+// DummyToken returns the access token of the dummy user of the given type.
+func (c *ClientWithResponses) DummyToken(ctx context.Context, userType UserType) (string, error) {
+	response, err := c.DummyLoginWithResponse(ctx, &DummyLoginParams{UserType: userType})
+	if err != nil {
+		return ``, err
+	}
+
+	if response.JSON200 == nil {
+		return ``, fmt.Errorf(`unexpected status code: %d`, response.StatusCode())
+	}
+
+	return response.JSON200.Token, nil
+}
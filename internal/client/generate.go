@@ -0,0 +1,4 @@
+// Package client is a typed client of the service generated from api.yaml.
+package client
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen --config=oapi-codegen.yaml ../../api.yaml
@@ -0,0 +1,295 @@
+// Package config holds the settings of the server and avito-admin. They are
+// read from an optional YAML file and then from environment variables, so a
+// variable always wins over the file.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the YAML file read by Load, if set.
+const FileEnv = `CONFIG_FILE`
+
+type Config struct {
+	HTTP     HTTP     `yaml:"http"`
+	Postgres Postgres `yaml:"postgres"`
+	Redis    Redis    `yaml:"redis"`
+	JWT      JWT      `yaml:"jwt"`
+	Auth     Auth     `yaml:"auth"`
+}
+
+type HTTP struct {
+	Addr              string        `yaml:"addr"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout is how long requests in flight may run after SIGTERM.
+	// It has to be shorter than the grace period of the container runtime.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type Postgres struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	DBName   string `yaml:"dbname"`
+	SSLMode  string `yaml:"sslmode"`
+	// Seed adds the demo data to an empty database at startup.
+	Seed bool `yaml:"seed"`
+	// ModerationLease is how long a flat stays locked by the moderator who took it.
+	ModerationLease time.Duration `yaml:"moderation_lease"`
+	// QueryTimeout bounds each storage call, even when the request has no deadline.
+	QueryTimeout time.Duration `yaml:"query_timeout"`
+	// Reopen enables status changes that take a moderated flat back into moderation.
+	Reopen Reopen `yaml:"reopen"`
+}
+
+// Reopen mirrors models.ReopenRules, all rules are disabled by default.
+type Reopen struct {
+	DeclinedToCreated      bool `yaml:"declined_to_created"`
+	DeclinedToOnModeration bool `yaml:"declined_to_on_moderation"`
+	ApprovedToOnModeration bool `yaml:"approved_to_on_moderation"`
+}
+
+// DSN is the lib/pq connection string. Values are quoted, so an empty
+// password or one with spaces and quotes stays a single value.
+func (p Postgres) DSN() string {
+	return fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%d sslmode=%s",
+		quoteDSN(p.User), quoteDSN(p.Password), quoteDSN(p.DBName), quoteDSN(p.Host), p.Port, quoteDSN(p.SSLMode))
+}
+
+var dsnEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+func quoteDSN(value string) string {
+	return `'` + dsnEscaper.Replace(value) + `'`
+}
+
+type Redis struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+	// FlatsTTL is how long a page of house flats stays cached.
+	FlatsTTL time.Duration `yaml:"flats_ttl"`
+	// CommandTimeout bounds each cache call.
+	CommandTimeout time.Duration `yaml:"command_timeout"`
+}
+
+// JWT selects the signing keys, see auth.Load. Without either a random key is used.
+type JWT struct {
+	KeysFile string `yaml:"keys_file"`
+	Secret   string `yaml:"secret"`
+}
+
+type Auth struct {
+	DummyTokenTTL   time.Duration `yaml:"dummy_token_ttl"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+}
+
+// Default matches the services of docker-compose.yaml.
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Addr:              `:8080`,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   15 * time.Second,
+		},
+		Postgres: Postgres{
+			Host:            `db`,
+			Port:            5432,
+			User:            `postgres`,
+			Password:        `postgres`,
+			DBName:          `avitobootcamp`,
+			SSLMode:         `disable`,
+			ModerationLease: 30 * time.Minute,
+			QueryTimeout:    5 * time.Second,
+		},
+		Redis: Redis{
+			Addr:           `redis:6379`,
+			FlatsTTL:       5 * time.Minute,
+			CommandTimeout: time.Second,
+		},
+		Auth: Auth{
+			DummyTokenTTL:   15 * time.Minute,
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+	}
+}
+
+// Load reads the defaults, the file in CONFIG_FILE and the environment
+// variables, in this order, and validates the result.
+func Load() (Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv(FileEnv); path != `` {
+		if err := cfg.readFile(path); err != nil {
+			return cfg, err
+		}
+	}
+
+	if err := cfg.readEnv(os.LookupEnv); err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func (cfg *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// readEnv overrides the settings with the variables that are set.
+func (cfg *Config) readEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+
+	str := func(name string, dst *string) {
+		if value, ok := lookup(name); ok {
+			*dst = value
+		}
+	}
+
+	integer := func(name string, dst *int) {
+		if value, ok := lookup(name); ok {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a number", name, value))
+				return
+			}
+
+			*dst = parsed
+		}
+	}
+
+	boolean := func(name string, dst *bool) {
+		if value, ok := lookup(name); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a boolean", name, value))
+				return
+			}
+
+			*dst = parsed
+		}
+	}
+
+	duration := func(name string, dst *time.Duration) {
+		if value, ok := lookup(name); ok {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a duration", name, value))
+				return
+			}
+
+			*dst = parsed
+		}
+	}
+
+	str(`HTTP_ADDR`, &cfg.HTTP.Addr)
+	duration(`HTTP_READ_HEADER_TIMEOUT`, &cfg.HTTP.ReadHeaderTimeout)
+	duration(`HTTP_READ_TIMEOUT`, &cfg.HTTP.ReadTimeout)
+	duration(`HTTP_WRITE_TIMEOUT`, &cfg.HTTP.WriteTimeout)
+	duration(`HTTP_IDLE_TIMEOUT`, &cfg.HTTP.IdleTimeout)
+	duration(`HTTP_SHUTDOWN_TIMEOUT`, &cfg.HTTP.ShutdownTimeout)
+
+	str(`DB_HOST`, &cfg.Postgres.Host)
+	integer(`DB_PORT`, &cfg.Postgres.Port)
+	str(`DB_USER`, &cfg.Postgres.User)
+	str(`DB_PASSWORD`, &cfg.Postgres.Password)
+	str(`DB_NAME`, &cfg.Postgres.DBName)
+	str(`DB_SSLMODE`, &cfg.Postgres.SSLMode)
+	boolean(`DB_SEED`, &cfg.Postgres.Seed)
+	duration(`MODERATION_LEASE`, &cfg.Postgres.ModerationLease)
+	duration(`DB_QUERY_TIMEOUT`, &cfg.Postgres.QueryTimeout)
+	boolean(`REOPEN_DECLINED_TO_CREATED`, &cfg.Postgres.Reopen.DeclinedToCreated)
+	boolean(`REOPEN_DECLINED_TO_ON_MODERATION`, &cfg.Postgres.Reopen.DeclinedToOnModeration)
+	boolean(`REOPEN_APPROVED_TO_ON_MODERATION`, &cfg.Postgres.Reopen.ApprovedToOnModeration)
+
+	str(`REDIS_ADDR`, &cfg.Redis.Addr)
+	str(`REDIS_PASSWORD`, &cfg.Redis.Password)
+	integer(`REDIS_DB`, &cfg.Redis.DB)
+	duration(`FLATS_CACHE_TTL`, &cfg.Redis.FlatsTTL)
+	duration(`REDIS_COMMAND_TIMEOUT`, &cfg.Redis.CommandTimeout)
+
+	str(`JWT_KEYS_FILE`, &cfg.JWT.KeysFile)
+	str(`JWT_SECRET`, &cfg.JWT.Secret)
+
+	duration(`DUMMY_TOKEN_TTL`, &cfg.Auth.DummyTokenTTL)
+	duration(`ACCESS_TOKEN_TTL`, &cfg.Auth.AccessTokenTTL)
+	duration(`REFRESH_TOKEN_TTL`, &cfg.Auth.RefreshTokenTTL)
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid setting at once.
+func (cfg Config) Validate() error {
+	var errs []error
+
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	_, _, err := net.SplitHostPort(cfg.HTTP.Addr)
+	check(err == nil, "http.addr: %q is not a host:port address", cfg.HTTP.Addr)
+	check(cfg.HTTP.ReadHeaderTimeout > 0, "http.read_header_timeout must be positive")
+	check(cfg.HTTP.ReadTimeout >= cfg.HTTP.ReadHeaderTimeout, "http.read_timeout must not be shorter than http.read_header_timeout")
+	check(cfg.HTTP.WriteTimeout > 0, "http.write_timeout must be positive")
+	check(cfg.HTTP.IdleTimeout > 0, "http.idle_timeout must be positive")
+	check(cfg.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout must be positive")
+
+	check(cfg.Postgres.Host != ``, "postgres.host is required")
+	check(cfg.Postgres.Port > 0 && cfg.Postgres.Port < 65536, "postgres.port: %d is out of range", cfg.Postgres.Port)
+	check(cfg.Postgres.User != ``, "postgres.user is required")
+	check(cfg.Postgres.DBName != ``, "postgres.dbname is required")
+	check(sslModes[cfg.Postgres.SSLMode], "postgres.sslmode: unknown mode %q", cfg.Postgres.SSLMode)
+	check(cfg.Postgres.ModerationLease > 0, "postgres.moderation_lease must be positive")
+	check(cfg.Postgres.QueryTimeout > 0, "postgres.query_timeout must be positive")
+
+	_, _, err = net.SplitHostPort(cfg.Redis.Addr)
+	check(err == nil, "redis.addr: %q is not a host:port address", cfg.Redis.Addr)
+	check(cfg.Redis.DB >= 0, "redis.db must not be negative")
+	check(cfg.Redis.FlatsTTL > 0, "redis.flats_ttl must be positive")
+	check(cfg.Redis.CommandTimeout > 0, "redis.command_timeout must be positive")
+
+	check(cfg.Auth.DummyTokenTTL > 0, "auth.dummy_token_ttl must be positive")
+	check(cfg.Auth.AccessTokenTTL > 0, "auth.access_token_ttl must be positive")
+	check(cfg.Auth.RefreshTokenTTL > cfg.Auth.AccessTokenTTL, "auth.refresh_token_ttl must be longer than auth.access_token_ttl")
+
+	return errors.Join(errs...)
+}
+
+var sslModes = map[string]bool{
+	`disable`:     true,
+	`require`:     true,
+	`verify-ca`:   true,
+	`verify-full`: true,
+}
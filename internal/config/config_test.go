@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), `config.yaml`)
+	err := os.WriteFile(path, []byte(`
+postgres:
+  host: file-host
+  port: 6543
+  reopen:
+    declined_to_created: true
+redis:
+  flats_ttl: 1m
+auth:
+  dummy_token_ttl: 5m
+`), 0o600)
+	assert.NoError(t, err)
+
+	t.Setenv(FileEnv, path)
+	t.Setenv(`DB_HOST`, `env-host`)
+	t.Setenv(`DB_SEED`, `true`)
+	t.Setenv(`REOPEN_APPROVED_TO_ON_MODERATION`, `true`)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+
+	// Тест 1: Переменная окружения важнее файла
+	assert.Equal(t, `env-host`, cfg.Postgres.Host)
+	assert.True(t, cfg.Postgres.Seed)
+
+	// Тест 2: Значения из файла, в том числе длительности
+	assert.Equal(t, 6543, cfg.Postgres.Port)
+	assert.Equal(t, time.Minute, cfg.Redis.FlatsTTL)
+	assert.Equal(t, 5*time.Minute, cfg.Auth.DummyTokenTTL)
+	assert.Equal(t, Reopen{DeclinedToCreated: true, ApprovedToOnModeration: true}, cfg.Postgres.Reopen)
+
+	// Тест 3: Не заданные значения берутся по умолчанию
+	assert.Equal(t, Default().HTTP, cfg.HTTP)
+	assert.Equal(t, Default().Auth.RefreshTokenTTL, cfg.Auth.RefreshTokenTTL)
+}
+
+func TestLoadErrors(t *testing.T) {
+	// Тест 1: Неизвестное поле в файле
+	path := filepath.Join(t.TempDir(), `config.yaml`)
+	assert.NoError(t, os.WriteFile(path, []byte("postgres:\n  hots: db\n"), 0o600))
+	t.Setenv(FileEnv, path)
+
+	_, err := Load()
+	assert.ErrorContains(t, err, `hots`)
+
+	// Тест 2: Некорректные переменные окружения
+	t.Setenv(FileEnv, ``)
+	t.Setenv(`DB_PORT`, `five`)
+	t.Setenv(`FLATS_CACHE_TTL`, `5`)
+
+	_, err = Load()
+	assert.ErrorContains(t, err, `DB_PORT`)
+	assert.ErrorContains(t, err, `FLATS_CACHE_TTL`)
+}
+
+func TestDSN(t *testing.T) {
+	testCases := []struct {
+		password string
+		expected string
+	}{
+		// Тест 1: Пустой пароль не захватывает следующий параметр
+		{
+			password: ``,
+			expected: `password=''`,
+		},
+		// Тест 2: Пароль с пробелом
+		{
+			password: `correct horse`,
+			expected: `password='correct horse'`,
+		},
+		// Тест 3: Пароль с кавычкой и обратной косой чертой
+		{
+			password: `it's\ok`,
+			expected: `password='it\'s\\ok'`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.password, func(t *testing.T) {
+			cfg := Default().Postgres
+			cfg.Password = tc.password
+
+			dsn := cfg.DSN()
+			assert.Contains(t, dsn, tc.expected+` dbname='avitobootcamp'`)
+
+			_, err := pq.NewConnector(dsn)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// Тест 1: Значения по умолчанию корректны
+	assert.NoError(t, Default().Validate())
+
+	// Тест 2: Все ошибки возвращаются сразу
+	cfg := Default()
+	cfg.HTTP.Addr = `8080`
+	cfg.Postgres.SSLMode = `maybe`
+	cfg.Redis.FlatsTTL = 0
+	cfg.Auth.RefreshTokenTTL = time.Minute
+
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, `http.addr`)
+	assert.ErrorContains(t, err, `postgres.sslmode`)
+	assert.ErrorContains(t, err, `redis.flats_ttl`)
+	assert.ErrorContains(t, err, `auth.refresh_token_ttl`)
+}
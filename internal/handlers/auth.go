@@ -1,142 +1,208 @@
 package handlers
 
 import (
+	"avitoBootcamp/internal/api"
+	"avitoBootcamp/internal/apierror"
+	"avitoBootcamp/internal/auth"
 	"avitoBootcamp/internal/models"
 	"avitoBootcamp/internal/storage"
-	"encoding/json"
-	"io"
-	"net/http"
+	"context"
+	"errors"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
-	"golang.org/x/crypto/bcrypt"
 )
 
-const jwtKey string = `B2iDZ6286IOLg8O1/f81Zdzh1BglfKTdLVw6twOqZGs=`
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
 
-func DummyLoginHandler(w http.ResponseWriter, r *http.Request) {
-	userType := r.URL.Query().Get(`user_type`)
+	dummyPasswordHash = sync.OnceValue(func() string {
+		hash, _ := auth.HashPassword(`dummy password`)
+		return hash
+	})
+)
+
+func (s *Server) DummyLogin(ctx context.Context, request api.DummyLoginRequestObject) (api.DummyLoginResponseObject, error) {
+	userType := string(request.Params.UserType)
 
-	if userType != `client` && userType != `moderator` {
-		w.Header().Set("Retry-After", "3")
-		http.Error(w, "No such user type", http.StatusInternalServerError)
-		return
+	userId := models.DummyClientId
+	if userType == `moderator` {
+		userId = models.DummyModeratorId
 	}
 
-	expirationTime := time.Now().Add(15 * time.Minute)
-	claims := &models.CustomClaims{
-		UserId: `dummyLogin`,
-		Type:   userType,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
+	tokenStr, err := signAccessToken(s.keys, userId, userType, s.ttl.DummyTokenTTL)
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString([]byte(jwtKey))
+	return api.DummyLogin200JSONResponse{Token: tokenStr}, nil
+}
+
+func (s *Server) Register(ctx context.Context, request api.RegisterRequestObject) (api.RegisterResponseObject, error) {
+	user := *request.Body
+
+	passwordHash, err := auth.HashPassword(user.Password)
 	if err != nil {
+		return nil, err
+	}
+
+	user.Password = passwordHash
+
+	user, err = s.db.CreateUser(ctx, user)
+
+	if errors.Is(err, storage.ErrEmailTaken) {
+		return nil, apierror.ErrEmailTaken
+	}
 
-		w.Header().Set("Retry-After", "3")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	if err != nil {
+		return nil, err
 	}
 
-	w.Header().Set("Content-type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(models.AuthorizationToken{Token: tokenStr})
+	return api.Register200JSONResponse{UserId: &user.Id}, nil
 }
 
-func RegisterHandler(db storage.Database) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+func (s *Server) Login(ctx context.Context, request api.LoginRequestObject) (api.LoginResponseObject, error) {
+	userFromReq := *request.Body
 
-		defer r.Body.Close()
+	if userFromReq.Email == `` && userFromReq.Id == `` {
+		return nil, apierror.BadRequest(`Email or id is required`)
+	}
 
-		var user models.User
-		if err := json.Unmarshal(body, &user); err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	user, err := findUserForLogin(ctx, s.db, userFromReq)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
 
-		passwordHash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
-		if err != nil {
-			w.Header().Set(`Retry-After`, "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	// An unknown user is checked against a dummy hash, so the response and
+	// its timing are the same as for a wrong password.
+	passwordHash := user.Password
+	if err != nil {
+		passwordHash = dummyPasswordHash()
+	}
 
-		user.Password = string(passwordHash)
+	if !auth.CheckPassword(passwordHash, userFromReq.Password) || err != nil {
+		return nil, apierror.ErrInvalidCredentials
+	}
 
-		if user, err = db.CreateUser(user); err != nil {
-			w.Header().Set(`Retry-After`, "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	tokens, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{"user_id": user.Id})
+	return api.Login200JSONResponse(tokens), nil
+}
 
+func (s *Server) RefreshToken(ctx context.Context, request api.RefreshTokenRequestObject) (api.RefreshTokenResponseObject, error) {
+	if request.Body.RefreshToken == `` {
+		return nil, apierror.BadRequest(`Invalid refresh token`)
+	}
+
+	refreshTokenStr, refreshTokenHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	oldToken, err := s.db.RotateRefreshToken(ctx, auth.HashRefreshToken(request.Body.RefreshToken), models.RefreshToken{
+		Hash:      refreshTokenHash,
+		ExpiresAt: time.Now().Add(s.ttl.RefreshTokenTTL),
 	})
+
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrRefreshTokenReused) {
+		return nil, apierror.ErrInvalidRefreshToken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.db.GetUserById(ctx, oldToken.UserId)
+	if err != nil {
+		return nil, apierror.ErrInvalidRefreshToken
+	}
+
+	tokenStr, err := signAccessToken(s.keys, user.Id, user.UserType, s.ttl.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.RefreshToken200JSONResponse{Token: tokenStr, RefreshToken: refreshTokenStr}, nil
 }
 
-func LoginHandler(db storage.Database) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			w.Header().Set(`Retry-After`, "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+// Logout revokes the access token used for the request and, if given, the
+// refresh token with every token rotated from it.
+func (s *Server) Logout(ctx context.Context, request api.LogoutRequestObject) (api.LogoutResponseObject, error) {
+	claims, ok := ctx.Value(`claims`).(*models.CustomClaims)
 
-		defer r.Body.Close()
+	if !ok {
+		return nil, errors.New(`could not get token claims`)
+	}
 
-		var userFromReq models.User
-		if err := json.Unmarshal(body, &userFromReq); err != nil {
-			w.Header().Set(`Retry-After`, "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+	if request.Body != nil && request.Body.RefreshToken != `` {
+		if err := s.db.RevokeRefreshToken(ctx, auth.HashRefreshToken(request.Body.RefreshToken), claims.UserId); err != nil {
+			return nil, err
 		}
+	}
 
-		user, err := db.GetUserById(userFromReq.Id)
-		if err != nil {
-			w.Header().Set(`Retry-After`, "3")
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
+	ttl := s.ttl.AccessTokenTTL
+	if claims.ExpiresAt != nil {
+		ttl = time.Until(claims.ExpiresAt.Time)
+	}
 
-		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(userFromReq.Password)); err != nil {
-			w.Header().Set(`Retry-After`, "3")
-			http.Error(w, "Invalid password", http.StatusBadRequest)
-			return
-		}
+	if err := s.cache.RevokeToken(ctx, claims.ID, ttl); err != nil {
+		return nil, err
+	}
 
-		expirationTime := time.Now().Add(48 * time.Hour)
-		claims := &models.CustomClaims{
-			UserId: user.Id,
-			Type:   user.UserType,
-			RegisteredClaims: jwt.RegisteredClaims{
-				ExpiresAt: jwt.NewNumericDate(expirationTime),
-			},
-		}
+	return api.Logout200Response{}, nil
+}
 
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-		tokenStr, err := token.SignedString([]byte(jwtKey))
-		if err != nil {
+func findUserForLogin(ctx context.Context, db storage.Database, userFromReq models.User) (models.User, error) {
+	if userFromReq.Email != `` {
+		return db.GetUserByEmail(ctx, userFromReq.Email)
+	}
 
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	if !uuidPattern.MatchString(userFromReq.Id) {
+		return models.User{}, storage.ErrNotFound
+	}
 
-		w.Header().Set("Content-type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(models.AuthorizationToken{Token: tokenStr})
+	return db.GetUserById(ctx, userFromReq.Id)
+}
+
+func signAccessToken(keys *auth.Keyring, userId string, userType string, ttl time.Duration) (string, error) {
+	tokenId, err := auth.NewTokenId()
+	if err != nil {
+		return ``, err
+	}
+
+	claims := &models.CustomClaims{
+		UserId: userId,
+		Type:   userType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenId,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+
+	return keys.Sign(claims)
+}
+
+func (s *Server) issueTokens(ctx context.Context, user models.User) (models.AuthorizationToken, error) {
+	tokenStr, err := signAccessToken(s.keys, user.Id, user.UserType, s.ttl.AccessTokenTTL)
+	if err != nil {
+		return models.AuthorizationToken{}, err
+	}
+
+	refreshTokenStr, refreshTokenHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return models.AuthorizationToken{}, err
+	}
+
+	err = s.db.CreateRefreshToken(ctx, models.RefreshToken{
+		Hash:      refreshTokenHash,
+		UserId:    user.Id,
+		ExpiresAt: time.Now().Add(s.ttl.RefreshTokenTTL),
 	})
+
+	return models.AuthorizationToken{Token: tokenStr, RefreshToken: refreshTokenStr}, err
 }
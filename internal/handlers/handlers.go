@@ -1,216 +1,441 @@
 package handlers
 
 import (
-	"encoding/json"
-	"io"
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
-	"strconv"
+	"net/mail"
 
+	"avitoBootcamp/internal/api"
+	"avitoBootcamp/internal/apierror"
 	"avitoBootcamp/internal/models"
 	"avitoBootcamp/internal/storage"
 
-	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
 )
 
-func HouseCreateHandler(db storage.Database) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		body, err := io.ReadAll(r.Body)
-		var house models.House
+func (s *Server) CreateHouse(ctx context.Context, request api.CreateHouseRequestObject) (api.CreateHouseResponseObject, error) {
+	house, err := s.db.CreateHouse(ctx, *request.Body)
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	return api.CreateHouse200JSONResponse(house), nil
+}
+
+func (s *Server) CreateFlat(ctx context.Context, request api.CreateFlatRequestObject) (api.CreateFlatResponseObject, error) {
+	flat := *request.Body
+
+	userId, err := userIdFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	flat.OwnerId = userId
+
+	flat, err = s.db.CreateFlat(ctx, flat)
+
+	if err := s.flatChanged(ctx, flat, err, false); err != nil {
+		return nil, err
+	}
+
+	return api.CreateFlat200JSONResponse(flat), nil
+}
+
+// ReleaseFlat gives a flat taken for moderation back to the queue.
+func (s *Server) ReleaseFlat(ctx context.Context, request api.ReleaseFlatRequestObject) (api.ReleaseFlatResponseObject, error) {
+	flat, err := s.updateFlatStatus(ctx, request.Id, models.StatusCreated)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.ReleaseFlat200JSONResponse(flat), nil
+}
+
+// ExtendFlat renews the moderation lease of the calling moderator.
+func (s *Server) ExtendFlat(ctx context.Context, request api.ExtendFlatRequestObject) (api.ExtendFlatResponseObject, error) {
+	flat, err := s.updateFlatStatus(ctx, request.Id, models.StatusOnModeration)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.ExtendFlat200JSONResponse(flat), nil
+}
+
+func (s *Server) updateFlatStatus(ctx context.Context, flatId int64, status string) (models.Flat, error) {
+	moderatorId, err := userIdFrom(ctx)
+
+	if err != nil {
+		return models.Flat{}, err
+	}
+
+	flat, err := s.db.UpdateFlat(ctx, models.Flat{Id: flatId, Status: status, ModeratorId: moderatorId})
+
+	return flat, s.flatChanged(ctx, flat, err, false)
+}
+
+// flatError maps the storage errors of flat changes to application errors.
+func flatError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return apierror.ErrFlatNotFound
+	case errors.Is(err, storage.ErrInvalidStatus):
+		return apierror.ErrInvalidFlatStatus
+	case errors.Is(err, storage.ErrInvalidDeclineReason):
+		return apierror.ErrInvalidDeclineReason
+	case errors.Is(err, storage.ErrNotFlatOwner):
+		return apierror.ErrNotFlatOwner
+	case errors.Is(err, storage.ErrFlatNumberTaken):
+		return apierror.ErrFlatNumberTaken
+	case errors.Is(err, storage.ErrHouseDeleted):
+		return apierror.ErrHouseDeleted
+	case errors.Is(err, storage.ErrHouseNotFound):
+		return apierror.ErrHouseNotFound
+	case errors.Is(err, storage.ErrFlatLocked):
+		return apierror.ErrFlatLocked
+	case errors.Is(err, storage.ErrInvalidStatusTransition):
+		return apierror.ErrInvalidStatusTransition
+	default:
+		return err
+	}
+}
+
+// flatChanged maps the error of a flat change to an application error, or on
+// success drops the cached flat lists of the house. Client lists are dropped
+// for approved flats, and always with clearClients for changes that may hide
+// a flat from clients. The change is committed by now, so the cache is
+// cleared even if the client has gone away.
+func (s *Server) flatChanged(ctx context.Context, flat models.Flat, err error, clearClients bool) error {
+	if err != nil {
+		return flatError(err)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	s.cache.DeleteFlatsByHouseId(ctx, flat.HouseId, `moderator`)
+	if clearClients || flat.Status == `approved` {
+		s.cache.DeleteFlatsByHouseId(ctx, flat.HouseId, `client`)
+	}
+
+	return nil
+}
+
+func (s *Server) UpdateFlat(ctx context.Context, request api.UpdateFlatRequestObject) (api.UpdateFlatResponseObject, error) {
+	flat := *request.Body
+
+	moderatorId, err := userIdFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !models.IsValidStatus(flat.Status) {
+		return nil, apierror.ErrInvalidFlatStatus
+	}
+
+	if flat.Status == models.StatusDeclined && (flat.DeclineReasonCode == `` || flat.DeclineReason == ``) {
+		return nil, apierror.BadRequest(`Declining requires decline_reason_code and decline_reason`)
+	}
+
+	flat.ModeratorId = moderatorId
+
+	flat, err = s.db.UpdateFlat(ctx, flat)
+
+	// A flat taken for moderation may have been approved and reopened, which
+	// hides it from clients.
+	if err := s.flatChanged(ctx, flat, err, flat.Status == models.StatusOnModeration); err != nil {
+		return nil, err
+	}
+
+	return api.UpdateFlat200JSONResponse(flat), nil
+}
+
+// ResubmitFlat lets the owner of a declined flat fix it and send it back to
+// moderation.
+func (s *Server) ResubmitFlat(ctx context.Context, request api.ResubmitFlatRequestObject) (api.ResubmitFlatResponseObject, error) {
+	edit := flatEdit(request.Body)
+
+	userId, err := userIdFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	flat, err := s.db.ResubmitFlat(ctx, request.Id, userId, edit)
+
+	if err := s.flatChanged(ctx, flat, err, false); err != nil {
+		return nil, err
+	}
+
+	return api.ResubmitFlat200JSONResponse(flat), nil
+}
+
+// EditFlat lets the owner change the price, rooms and number of a flat.
+// Editing an approved flat sends it back to moderation.
+func (s *Server) EditFlat(ctx context.Context, request api.EditFlatRequestObject) (api.EditFlatResponseObject, error) {
+	edit := flatEdit(request.Body)
+
+	if edit.IsEmpty() {
+		return nil, apierror.BadRequest(`Nothing to change`)
+	}
+
+	userId, err := userIdFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	flat, err := s.db.EditFlat(ctx, request.Id, userId, edit)
+
+	// The flat may have been approved before the edit.
+	if err := s.flatChanged(ctx, flat, err, true); err != nil {
+		return nil, err
+	}
+
+	return api.EditFlat200JSONResponse(flat), nil
+}
 
-		defer r.Body.Close()
+// flatEdit returns the optional body of a flat edit, the spec checks its values.
+func flatEdit(body *models.FlatEdit) models.FlatEdit {
+	if body == nil {
+		return models.FlatEdit{}
+	}
 
-		if err := json.Unmarshal(body, &house); err != nil {
+	return *body
+}
+
+// DeleteFlat archives a flat. Moderators may archive any flat, other users
+// only their own.
+func (s *Server) DeleteFlat(ctx context.Context, request api.DeleteFlatRequestObject) (api.DeleteFlatResponseObject, error) {
+	userType, err := userTypeFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userId, err := userIdFrom(ctx)
 
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+	if err != nil {
+		return nil, err
+	}
+
+	if userType != `moderator` {
+		flat, err := s.db.GetFlatById(ctx, request.Id)
+
+		if err == nil && flat.OwnerId != userId {
+			err = storage.ErrNotFlatOwner
 		}
 
-		house, err = db.CreateHouse(house)
 		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			return nil, flatError(err)
 		}
+	}
 
-		jsonResponse, err := json.Marshal(house)
+	flat, err := s.db.DeleteFlat(ctx, request.Id)
 
-		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	if err := s.flatChanged(ctx, flat, err, true); err != nil {
+		return nil, err
+	}
+
+	return api.DeleteFlat200JSONResponse(flat), nil
+}
+
+func (s *Server) RestoreFlat(ctx context.Context, request api.RestoreFlatRequestObject) (api.RestoreFlatResponseObject, error) {
+	flat, err := s.db.RestoreFlat(ctx, request.Id)
 
-		w.Header().Set(`Content-Type`, `application/json`)
-		w.WriteHeader(http.StatusOK)
-		w.Write(jsonResponse)
+	if err := s.flatChanged(ctx, flat, err, true); err != nil {
+		return nil, err
+	}
 
-	})
+	return api.RestoreFlat200JSONResponse(flat), nil
 }
 
-func FlatCreateHandler(db storage.Database, cache storage.Cache) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var flat models.Flat
-		body, err := io.ReadAll(r.Body)
+// GetMyFlats returns the flats of the calling user in all statuses.
+func (s *Server) GetMyFlats(ctx context.Context, request api.GetMyFlatsRequestObject) (api.GetMyFlatsResponseObject, error) {
+	userId, err := userIdFrom(ctx)
 
-		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		defer r.Body.Close()
+	flats, err := s.db.GetFlatsByOwner(ctx, userId)
 
-		if err := json.Unmarshal(body, &flat); err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		flat, err = db.CreateFlat(flat)
+	return api.GetMyFlats200JSONResponse{Flats: flats}, nil
+}
 
-		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+// GetFlatHistory returns the status history of a flat to moderators and to
+// the owner of the flat.
+func (s *Server) GetFlatHistory(ctx context.Context, request api.GetFlatHistoryRequestObject) (api.GetFlatHistoryResponseObject, error) {
+	userType, err := userTypeFrom(ctx)
 
-		jsonResponse, err := json.Marshal(flat)
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+	userId, err := userIdFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userType != `moderator` {
+		flat, err := s.db.GetFlatById(ctx, request.Id)
+
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, apierror.ErrFlatNotFound
 		}
 
-		cache.DeleteFlatsByHouseId(flat.HouseId, `moderator`)
-		if flat.Status == `approved` {
-			cache.DeleteFlatsByHouseId(flat.HouseId, `client`)
+		if err != nil {
+			return nil, err
 		}
 
-		if err := db.UpdateAtHouseLastFlatTime(flat.HouseId); err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		if flat.OwnerId != userId {
+			return nil, apierror.ErrFlatHistoryForbidden
 		}
+	}
+
+	history, err := s.db.GetFlatStatusHistory(ctx, request.Id)
+
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, apierror.ErrFlatNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
 
-		w.Header().Set(`Content-Type`, `application/json`)
-		w.WriteHeader(http.StatusOK)
-		w.Write(jsonResponse)
-	})
+	return api.GetFlatHistory200JSONResponse{History: history}, nil
 }
 
-func FlatUpdateHandler(db storage.Database, cache storage.Cache) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		body, err := io.ReadAll(r.Body)
-		var flat models.Flat
+func (s *Server) Subscribe(ctx context.Context, request api.SubscribeRequestObject) (api.SubscribeResponseObject, error) {
+	subscription := *request.Body
 
-		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	if _, err := mail.ParseAddress(subscription.Email); err != nil {
+		return nil, apierror.BadRequest(`Invalid email`)
+	}
 
-		defer r.Body.Close()
+	subscription.HouseId = request.Id
 
-		if err := json.Unmarshal(body, &flat); err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	_, err := s.db.CreateSubscription(ctx, subscription)
 
-		flat, err = db.UpdateFlat(flat)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, apierror.ErrHouseNotFound
+	}
 
-		if flat.Id == -1 {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, `This apartment is being moderated by another moderator`, http.StatusUnauthorized)
+	if err != nil {
+		return nil, err
+	}
 
-		}
+	return api.Subscribe200Response{}, nil
+}
 
-		if err != nil {
+// DeleteHouse archives a house together with its flats.
+func (s *Server) DeleteHouse(ctx context.Context, request api.DeleteHouseRequestObject) (api.DeleteHouseResponseObject, error) {
+	house, err := s.houseArchiveChanged(ctx, request.Id, s.db.DeleteHouse)
 
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		jsonResponse, err := json.Marshal(flat)
+	return api.DeleteHouse200JSONResponse(house), nil
+}
 
-		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+// RestoreHouse brings back a house and the flats archived with it.
+func (s *Server) RestoreHouse(ctx context.Context, request api.RestoreHouseRequestObject) (api.RestoreHouseResponseObject, error) {
+	house, err := s.houseArchiveChanged(ctx, request.Id, s.db.RestoreHouse)
 
-		cache.DeleteFlatsByHouseId(flat.HouseId, `moderator`)
-		if flat.Status == `approved` {
-			cache.DeleteFlatsByHouseId(flat.HouseId, `client`)
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		w.Header().Set(`Content-Type`, `application/json`)
-		w.WriteHeader(http.StatusOK)
-		w.Write(jsonResponse)
-	})
+	return api.RestoreHouse200JSONResponse(house), nil
 }
 
-func GetFlatsInHouseHandler(db storage.Database, cache storage.Cache) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		parameters := mux.Vars(r)
+func (s *Server) houseArchiveChanged(ctx context.Context, houseId int64, change func(ctx context.Context, houseId int64) (models.House, error)) (models.House, error) {
+	house, err := change(ctx, houseId)
 
-		houseId, err := strconv.ParseInt(parameters[`id`], 10, 64)
+	if errors.Is(err, storage.ErrNotFound) {
+		return house, apierror.ErrHouseNotFound
+	}
 
-		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	if err != nil {
+		return house, err
+	}
 
-		userType, ok := r.Context().Value(`userType`).(string)
+	ctx = context.WithoutCancel(ctx)
 
-		if !ok {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, `could not get a user type`, http.StatusInternalServerError)
-			return
-		}
+	s.cache.DeleteFlatsByHouseId(ctx, houseId, `moderator`)
+	s.cache.DeleteFlatsByHouseId(ctx, houseId, `client`)
 
-		jsonFlats, err := cache.GetFlatsByHouseID(houseId, userType)
+	return house, nil
+}
 
-		if err == nil {
-			slog.Info(`Flats gets from cache`, "houseID", houseId, "userType", userType)
-			w.Header().Set(`Content-Type`, `application/json`)
-			w.WriteHeader(http.StatusOK)
-			w.Write(jsonFlats)
+// cachedHouseFlats is a page of house flats as it is stored in the cache.
+type cachedHouseFlats []byte
 
-			return
-		}
+func (page cachedHouseFlats) VisitGetHouseFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set(`Content-Type`, `application/json`)
+	w.WriteHeader(http.StatusOK)
+
+	_, err := w.Write(page)
 
-		flats, err := db.GetFlatsByHouseID(houseId, userType)
+	return err
+}
+
+// GetHouseFlats returns a page of the house flats, see parseHouseFlatsQuery
+// for the paging, sorting and filters. Every query shape is cached on its own.
+func (s *Server) GetHouseFlats(ctx context.Context, request api.GetHouseFlatsRequestObject) (api.GetHouseFlatsResponseObject, error) {
+	houseId := request.Id
+
+	userType, err := userTypeFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := parseHouseFlatsQuery(request.Params, userType)
+
+	if err != nil {
+		return nil, apierror.BadRequest(err.Error())
+	}
+
+	// Archived flats are shown to moderators on request and never cached.
+	if query.IncludeDeleted {
+		page, err := s.db.GetFlatsByHouseID(ctx, houseId, userType, query)
 
 		if err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			return nil, err
 		}
 
-		if err := cache.PutFlatsByHouseID(flats, houseId, userType); err != nil {
-			slog.Error("Failed to cache flats", "houseID", houseId, "userType", userType, "error", err)
-		}
+		return api.GetHouseFlats200JSONResponse(page), nil
+	}
 
-		w.Header().Set(`Content-Type`, `application/json`)
-		w.WriteHeader(http.StatusOK)
+	queryKey := query.CacheKey()
+	jsonPage, err := s.cache.GetFlatsByHouseID(ctx, houseId, userType, queryKey)
 
-		if err := json.NewEncoder(w).Encode(flats); err != nil {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-	})
+	if err == nil {
+		slog.Info(`Flats gets from cache`, "houseID", houseId, "userType", userType)
+
+		return cachedHouseFlats(jsonPage), nil
+	}
+
+	page, err := s.db.GetFlatsByHouseID(ctx, houseId, userType, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.PutFlatsByHouseID(ctx, page, houseId, userType, queryKey); err != nil {
+		slog.Error("Failed to cache flats", "houseID", houseId, "userType", userType, "error", err)
+	}
+
+	return api.GetHouseFlats200JSONResponse(page), nil
 }
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"avitoBootcamp/internal/api"
+	"avitoBootcamp/internal/apierror"
+	"context"
+	"fmt"
+	"time"
+)
+
+// readinessTimeout bounds each dependency check, so a hung database fails
+// the probe instead of hanging it.
+const readinessTimeout = 2 * time.Second
+
+func (s *Server) Healthz(ctx context.Context, request api.HealthzRequestObject) (api.HealthzResponseObject, error) {
+	return api.Healthz200JSONResponse{Status: `ok`}, nil
+}
+
+func (s *Server) Readyz(ctx context.Context, request api.ReadyzRequestObject) (api.ReadyzResponseObject, error) {
+	checks := []struct {
+		name string
+		ping func(ctx context.Context) error
+	}{
+		{name: `database`, ping: s.db.Ping},
+		{name: `cache`, ping: s.cache.Ping},
+	}
+
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, readinessTimeout)
+		err := check.ping(checkCtx)
+		cancel()
+
+		if err != nil {
+			return nil, apierror.Unavailable(fmt.Errorf("%s: %w", check.name, err))
+		}
+	}
+
+	return api.Readyz200JSONResponse{Status: `ok`}, nil
+}
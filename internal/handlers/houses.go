@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"avitoBootcamp/internal/api"
+	"avitoBootcamp/internal/apierror"
+	"avitoBootcamp/internal/models"
+	"avitoBootcamp/internal/storage"
+)
+
+const (
+	defaultHousesLimit = 20
+	maxHouseTextLength = 1000
+)
+
+// GetHouse returns the house metadata. Archived houses are shown to
+// moderators only.
+func (s *Server) GetHouse(ctx context.Context, request api.GetHouseRequestObject) (api.GetHouseResponseObject, error) {
+	userType, err := userTypeFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	house, err := s.db.GetHouseById(ctx, request.Id)
+
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && house.DeletedAt != nil && userType != `moderator`) {
+		return nil, apierror.ErrHouseNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetHouse200JSONResponse(house), nil
+}
+
+// EditHouse lets a moderator correct the address, year and developer of a
+// house. Only the fields present in the body are changed.
+func (s *Server) EditHouse(ctx context.Context, request api.EditHouseRequestObject) (api.EditHouseResponseObject, error) {
+	edit, err := checkHouseEdit(request.Body)
+
+	if err != nil {
+		return nil, apierror.BadRequest(err.Error())
+	}
+
+	house, err := s.db.UpdateHouse(ctx, request.Id, edit)
+
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, apierror.ErrHouseNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.EditHouse200JSONResponse(house), nil
+}
+
+func checkHouseEdit(body *models.HouseEdit) (models.HouseEdit, error) {
+	var edit models.HouseEdit
+
+	if body != nil {
+		edit = *body
+	}
+
+	if edit.IsEmpty() {
+		return edit, errors.New(`Nothing to change`)
+	}
+
+	if edit.Address != nil {
+		address := strings.TrimSpace(*edit.Address)
+		if address == `` || len(address) > maxHouseTextLength {
+			return edit, errors.New(`Invalid address`)
+		}
+
+		edit.Address = &address
+	}
+
+	if edit.Developer != nil && len(*edit.Developer) > maxHouseTextLength {
+		return edit, errors.New(`Invalid developer`)
+	}
+
+	return edit, nil
+}
+
+// ListHouses lists houses page by page, see parseHouseFilter for the filters.
+func (s *Server) ListHouses(ctx context.Context, request api.ListHousesRequestObject) (api.ListHousesResponseObject, error) {
+	filter, err := parseHouseFilter(request.Params)
+
+	if err != nil {
+		return nil, apierror.BadRequest(err.Error())
+	}
+
+	page, err := s.db.GetHouses(ctx, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.ListHouses200JSONResponse(page), nil
+}
+
+func parseHouseFilter(params api.ListHousesParams) (models.HouseFilter, error) {
+	filter := models.HouseFilter{
+		Developer:    valueOr(params.Developer, ``),
+		YearMin:      params.YearMin,
+		YearMax:      params.YearMax,
+		UpdatedSince: params.UpdatedSince,
+		Limit:        valueOr(params.Limit, defaultHousesLimit),
+	}
+
+	if filter.YearMin != nil && filter.YearMax != nil && *filter.YearMin > *filter.YearMax {
+		return filter, errors.New(`year_min is greater than year_max`)
+	}
+
+	if params.Cursor != nil {
+		cursor, err := models.DecodeHouseCursor(*params.Cursor)
+		if err != nil {
+			return filter, err
+		}
+
+		filter.After = &cursor
+	}
+
+	return filter, nil
+}
+
+// parseHouseFlatsQuery reads the paging, sorting and filters of the house
+// flats. Without limit all flats are returned. include_deleted is ignored
+// for clients.
+func parseHouseFlatsQuery(params api.GetHouseFlatsParams, userType string) (models.HouseFlatsQuery, error) {
+	flatsQuery := models.HouseFlatsQuery{
+		Sort:           models.HouseFlatsSortFlatNum,
+		IncludeDeleted: userType == `moderator` && valueOr(params.IncludeDeleted, false),
+		RoomsMin:       params.RoomsMin,
+		RoomsMax:       params.RoomsMax,
+		PriceMin:       params.PriceMin,
+		PriceMax:       params.PriceMax,
+		Limit:          valueOr(params.Limit, 0),
+	}
+
+	if flatsQuery.RoomsMin != nil && flatsQuery.RoomsMax != nil && *flatsQuery.RoomsMin > *flatsQuery.RoomsMax {
+		return flatsQuery, errors.New(`rooms_min is greater than rooms_max`)
+	}
+
+	if flatsQuery.PriceMin != nil && flatsQuery.PriceMax != nil && *flatsQuery.PriceMin > *flatsQuery.PriceMax {
+		return flatsQuery, errors.New(`price_min is greater than price_max`)
+	}
+
+	if params.Sort != nil {
+		flatsQuery.Sort = string(*params.Sort)
+	}
+
+	if params.Cursor != nil {
+		cursor, err := models.DecodeHouseFlatsCursor(*params.Cursor)
+		if err != nil {
+			return flatsQuery, err
+		}
+
+		// A cursor only makes sense for the order it was issued for.
+		if cursor.Sort != flatsQuery.Sort {
+			return flatsQuery, models.ErrInvalidCursor
+		}
+
+		flatsQuery.After = &cursor
+	}
+
+	return flatsQuery, nil
+}
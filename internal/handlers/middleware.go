@@ -1,55 +1,119 @@
 package handlers
 
 import (
+	"avitoBootcamp/internal/api"
+	"avitoBootcamp/internal/apierror"
+	"avitoBootcamp/internal/auth"
 	"avitoBootcamp/internal/models"
 	"avitoBootcamp/internal/storage"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"net/http"
+	"regexp"
+	"slices"
 	"strings"
-
-	"github.com/golang-jwt/jwt/v4"
 )
 
-func AuthorizationMiddleware(next http.Handler, onlyModerator bool, db storage.Database) http.Handler {
+var requestIdPattern = regexp.MustCompile(`^[0-9A-Za-z-]{1,64}$`)
+
+// RequestIdMiddleware gives every request an id, which is sent back in the
+// X-Request-Id header and in error bodies. An id set by the caller is kept.
+func RequestIdMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, "Invalid authorization header", http.StatusUnauthorized)
-			return
+		requestId := r.Header.Get(`X-Request-Id`)
+
+		if !requestIdPattern.MatchString(requestId) {
+			id := make([]byte, 12)
+			rand.Read(id)
+			requestId = hex.EncodeToString(id)
 		}
 
-		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+		w.Header().Set(`X-Request-Id`, requestId)
 
-		claims := &models.CustomClaims{}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), `requestId`, requestId)))
+	})
+}
 
-		token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
-			return []byte(jwtKey), nil
-		})
+// NotFoundHandler answers requests to unknown routes with an error body.
+func NotFoundHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apierror.Write(w, r, apierror.ErrRouteNotFound)
+	})
+}
 
-		if err != nil || !token.Valid {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, err.Error(), http.StatusUnauthorized)
-			return
-		}
+// MethodNotAllowedHandler answers requests with a method the route does not serve.
+func MethodNotAllowedHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apierror.Write(w, r, apierror.ErrMethodNotAllowed)
+	})
+}
+
+// AuthorizationMiddleware checks the bearer token of the operations secured
+// in api.yaml. Operations with the moderator scope are served to moderators
+// only, operations without security are passed on. The type of a registered
+// user is taken from the database, so a promotion or demotion applies to the
+// tokens already issued.
+func AuthorizationMiddleware(db storage.Database, cache storage.Cache, keys *auth.Keyring) api.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, secured := r.Context().Value(api.BearerAuthScopes).([]string)
+			if !secured {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			authHeader := r.Header.Get("Authorization")
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				apierror.Write(w, r, apierror.ErrUnauthorized)
+				return
+			}
 
-		if claims.UserId != `dummyLogin` {
-			_, err := db.GetUserById(claims.UserId)
+			tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+
+			claims := &models.CustomClaims{}
+
+			token, err := keys.Parse(tokenStr, claims)
+
+			if err != nil || !token.Valid {
+				apierror.Write(w, r, apierror.ErrUnauthorized)
+				return
+			}
+
+			revoked, err := cache.IsTokenRevoked(r.Context(), claims.ID)
 			if err != nil {
-				w.Header().Set("Retry-After", "3")
-				http.Error(w, `Invalid authorization token`, http.StatusUnauthorized)
+				apierror.Write(w, r, errors.New(`could not check the authorization token`))
 				return
 			}
-		}
 
-		if onlyModerator && claims.Type != `moderator` {
-			w.Header().Set("Retry-After", "3")
-			http.Error(w, "You are not a moderator", http.StatusUnauthorized)
-			return
-		}
+			if revoked {
+				apierror.Write(w, r, apierror.ErrUnauthorized)
+				return
+			}
 
-		ctx := context.WithValue(r.Context(), `userType`, claims.Type)
+			userType := claims.Type
 
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+			if claims.UserId != models.DummyClientId && claims.UserId != models.DummyModeratorId {
+				user, err := db.GetUserById(r.Context(), claims.UserId)
+				if err != nil {
+					apierror.Write(w, r, apierror.ErrUnauthorized)
+					return
+				}
+
+				userType = user.UserType
+			}
+
+			if slices.Contains(scopes, `moderator`) && userType != `moderator` {
+				apierror.Write(w, r, apierror.ErrNotModerator)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), `userType`, userType)
+			ctx = context.WithValue(ctx, `userId`, claims.UserId)
+			ctx = context.WithValue(ctx, `claims`, claims)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
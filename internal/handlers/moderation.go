@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"avitoBootcamp/internal/api"
+	"avitoBootcamp/internal/apierror"
+	"avitoBootcamp/internal/models"
+	"avitoBootcamp/internal/storage"
+)
+
+const defaultQueueLimit = 20
+
+var declineReasonCodePattern = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+func (s *Server) GetModerationQueue(ctx context.Context, request api.GetModerationQueueRequestObject) (api.GetModerationQueueResponseObject, error) {
+	params := request.Params
+
+	filter, err := parseModerationQueueFilter(params.HouseId, params.Developer, params.PriceMin, params.PriceMax)
+
+	if err != nil {
+		return nil, apierror.BadRequest(err.Error())
+	}
+
+	filter.Limit = valueOr(params.Limit, defaultQueueLimit)
+
+	if params.Cursor != nil {
+		cursor, err := models.DecodeFlatCursor(*params.Cursor)
+
+		if err != nil {
+			return nil, apierror.BadRequest(err.Error())
+		}
+
+		filter.After = &cursor
+	}
+
+	page, err := s.db.GetModerationQueue(ctx, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetModerationQueue200JSONResponse(page), nil
+}
+
+// TakeNextFlat assigns the oldest flat from the queue to the calling
+// moderator. It accepts the same filters as the queue listing.
+func (s *Server) TakeNextFlat(ctx context.Context, request api.TakeNextFlatRequestObject) (api.TakeNextFlatResponseObject, error) {
+	params := request.Params
+
+	filter, err := parseModerationQueueFilter(params.HouseId, params.Developer, params.PriceMin, params.PriceMax)
+
+	if err != nil {
+		return nil, apierror.BadRequest(err.Error())
+	}
+
+	moderatorId, err := userIdFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	flat, err := s.db.TakeNextFlatForModeration(ctx, moderatorId, filter)
+
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, apierror.ErrModerationQueueEmpty
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.DeleteFlatsByHouseId(context.WithoutCancel(ctx), flat.HouseId, `moderator`)
+
+	return api.TakeNextFlat200JSONResponse(flat), nil
+}
+
+func parseModerationQueueFilter(houseId *int64, developer *string, priceMin *int64, priceMax *int64) (models.ModerationQueueFilter, error) {
+	filter := models.ModerationQueueFilter{
+		HouseId:   valueOr(houseId, 0),
+		Developer: valueOr(developer, ``),
+		PriceMin:  priceMin,
+		PriceMax:  priceMax,
+		Limit:     defaultQueueLimit,
+	}
+
+	if filter.PriceMin != nil && filter.PriceMax != nil && *filter.PriceMin > *filter.PriceMax {
+		return filter, errors.New(`price_min is greater than price_max`)
+	}
+
+	return filter, nil
+}
+
+func (s *Server) ListDeclineReasons(ctx context.Context, request api.ListDeclineReasonsRequestObject) (api.ListDeclineReasonsResponseObject, error) {
+	reasons, err := s.db.GetDeclineReasons(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.ListDeclineReasons200JSONResponse{Reasons: reasons}, nil
+}
+
+func (s *Server) SaveDeclineReason(ctx context.Context, request api.SaveDeclineReasonRequestObject) (api.SaveDeclineReasonResponseObject, error) {
+	reason := *request.Body
+
+	if !declineReasonCodePattern.MatchString(reason.Code) || reason.Description == `` {
+		return nil, apierror.ErrInvalidDeclineReason
+	}
+
+	reason, err := s.db.SaveDeclineReason(ctx, reason)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.SaveDeclineReason200JSONResponse(reason), nil
+}
+
+func (s *Server) DeleteDeclineReason(ctx context.Context, request api.DeleteDeclineReasonRequestObject) (api.DeleteDeclineReasonResponseObject, error) {
+	err := s.db.DeactivateDeclineReason(ctx, request.Code)
+
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, apierror.ErrDeclineReasonNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.DeleteDeclineReason200Response{}, nil
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"avitoBootcamp/internal/api"
+	"avitoBootcamp/internal/apierror"
+	"avitoBootcamp/internal/models"
+)
+
+const defaultSearchLimit = 20
+
+// SearchFlats searches flats across all houses, see parseFlatSearchFilter for
+// the filters. Clients get approved flats only.
+func (s *Server) SearchFlats(ctx context.Context, request api.SearchFlatsRequestObject) (api.SearchFlatsResponseObject, error) {
+	filter, err := parseFlatSearchFilter(request.Params)
+
+	if err != nil {
+		return nil, apierror.BadRequest(err.Error())
+	}
+
+	userType, err := userTypeFrom(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.db.SearchFlats(ctx, filter, userType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.SearchFlats200JSONResponse(page), nil
+}
+
+func parseFlatSearchFilter(params api.SearchFlatsParams) (models.FlatSearchFilter, error) {
+	filter := models.FlatSearchFilter{
+		Developer: valueOr(params.Developer, ``),
+		Address:   strings.TrimSpace(valueOr(params.Address, ``)),
+		PriceMin:  params.PriceMin,
+		PriceMax:  params.PriceMax,
+		RoomsMin:  params.RoomsMin,
+		RoomsMax:  params.RoomsMax,
+		YearMin:   params.YearMin,
+		YearMax:   params.YearMax,
+		Sort:      models.FlatSortNewest,
+		Limit:     valueOr(params.Limit, defaultSearchLimit),
+	}
+
+	if filter.PriceMin != nil && filter.PriceMax != nil && *filter.PriceMin > *filter.PriceMax {
+		return filter, errors.New(`price_min is greater than price_max`)
+	}
+
+	if filter.RoomsMin != nil && filter.RoomsMax != nil && *filter.RoomsMin > *filter.RoomsMax {
+		return filter, errors.New(`rooms_min is greater than rooms_max`)
+	}
+
+	if filter.YearMin != nil && filter.YearMax != nil && *filter.YearMin > *filter.YearMax {
+		return filter, errors.New(`year_min is greater than year_max`)
+	}
+
+	if params.Sort != nil {
+		filter.Sort = string(*params.Sort)
+	}
+
+	if params.Cursor != nil {
+		cursor, err := models.DecodeFlatSearchCursor(*params.Cursor)
+		if err != nil {
+			return filter, err
+		}
+
+		// A cursor only makes sense for the order it was issued for.
+		if cursor.Sort != filter.Sort {
+			return filter, models.ErrInvalidCursor
+		}
+
+		filter.After = &cursor
+	}
+
+	return filter, nil
+}
+
+// valueOr returns the optional query parameter or its default.
+func valueOr[T any](value *T, fallback T) T {
+	if value == nil {
+		return fallback
+	}
+
+	return *value
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"avitoBootcamp/internal/api"
+	"avitoBootcamp/internal/auth"
+	"avitoBootcamp/internal/config"
+	"avitoBootcamp/internal/storage"
+	"context"
+	"errors"
+)
+
+// Server implements the operations of api.yaml. Errors are returned as they
+// are and written by apierror.Write, see router.New.
+type Server struct {
+	db    storage.Database
+	cache storage.Cache
+	keys  *auth.Keyring
+	ttl   config.Auth
+}
+
+var _ api.StrictServerInterface = (*Server)(nil)
+
+func NewServer(db storage.Database, cache storage.Cache, keys *auth.Keyring, ttl config.Auth) *Server {
+	return &Server{db: db, cache: cache, keys: keys, ttl: ttl}
+}
+
+func userIdFrom(ctx context.Context) (string, error) {
+	userId, ok := ctx.Value(`userId`).(string)
+
+	if !ok {
+		return ``, errors.New(`could not get a user id`)
+	}
+
+	return userId, nil
+}
+
+func userTypeFrom(ctx context.Context) (string, error) {
+	userType, ok := ctx.Value(`userType`).(string)
+
+	if !ok {
+		return ``, errors.New(`could not get a user type`)
+	}
+
+	return userType, nil
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// FlatCursor points at the last flat of a page ordered by creation time. The
+// id breaks ties between flats created at the same moment.
+type FlatCursor struct {
+	CreatedAt time.Time
+	Id        int64
+}
+
+func (cursor FlatCursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", cursor.CreatedAt.UnixNano(), cursor.Id)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeFlatCursor(encoded string) (FlatCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return FlatCursor{}, ErrInvalidCursor
+	}
+
+	var createdAt int64
+	var cursor FlatCursor
+
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &createdAt, &cursor.Id); err != nil {
+		return FlatCursor{}, ErrInvalidCursor
+	}
+
+	cursor.CreatedAt = time.Unix(0, createdAt).UTC()
+
+	return cursor, nil
+}
+
+// HouseCursor points at the last house of a page ordered by id.
+type HouseCursor struct {
+	Id int64
+}
+
+func (cursor HouseCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(cursor.Id, 10)))
+}
+
+func DecodeHouseCursor(encoded string) (HouseCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return HouseCursor{}, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id < 1 {
+		return HouseCursor{}, ErrInvalidCursor
+	}
+
+	return HouseCursor{Id: id}, nil
+}
+
+// FlatSearchCursor points at the last flat of a search page. It remembers the
+// sort it was issued for, the price is only used by the price sorts.
+type FlatSearchCursor struct {
+	Sort  string
+	Price int64
+	Id    int64
+}
+
+func (cursor FlatSearchCursor) Encode() string {
+	return encodeSortCursor(cursor.Sort, cursor.Price, cursor.Id)
+}
+
+func DecodeFlatSearchCursor(encoded string) (FlatSearchCursor, error) {
+	sort, price, id, err := decodeSortCursor(encoded)
+
+	return FlatSearchCursor{Sort: sort, Price: price, Id: id}, err
+}
+
+// HouseFlatsCursor points at the last flat of a page of one house. Value is
+// the flat field the page is sorted by.
+type HouseFlatsCursor struct {
+	Sort  string
+	Value int64
+	Id    int64
+}
+
+func (cursor HouseFlatsCursor) Encode() string {
+	return encodeSortCursor(cursor.Sort, cursor.Value, cursor.Id)
+}
+
+func DecodeHouseFlatsCursor(encoded string) (HouseFlatsCursor, error) {
+	sort, value, id, err := decodeSortCursor(encoded)
+
+	return HouseFlatsCursor{Sort: sort, Value: value, Id: id}, err
+}
+
+func encodeSortCursor(sort string, value int64, id int64) string {
+	raw := fmt.Sprintf("%s:%d:%d", sort, value, id)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSortCursor(encoded string) (string, int64, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ``, 0, 0, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return ``, 0, 0, ErrInvalidCursor
+	}
+
+	value, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ``, 0, 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return ``, 0, 0, ErrInvalidCursor
+	}
+
+	return parts[0], value, id, nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatCursor(t *testing.T) {
+	cursor := FlatCursor{CreatedAt: time.Date(2024, 8, 9, 12, 30, 0, 123456000, time.UTC), Id: 42}
+
+	// Тест 1: Курсор восстанавливается после кодирования
+	decoded, err := DecodeFlatCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	// Тест 2: Произвольная строка не является курсором
+	_, err = DecodeFlatCursor("not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestHouseCursor(t *testing.T) {
+	cursor := HouseCursor{Id: 42}
+
+	// Тест 1: Курсор восстанавливается после кодирования
+	decoded, err := DecodeHouseCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	// Тест 2: Курсор квартиры не подходит для списка домов
+	_, err = DecodeHouseCursor(FlatCursor{CreatedAt: time.Now(), Id: 42}.Encode())
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestFlatSearchCursor(t *testing.T) {
+	cursor := FlatSearchCursor{Sort: FlatSortPriceDesc, Price: 150000, Id: 42}
+
+	// Тест 1: Курсор восстанавливается после кодирования
+	decoded, err := DecodeFlatSearchCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	// Тест 2: Курсор дома не подходит для поиска квартир
+	_, err = DecodeFlatSearchCursor(HouseCursor{Id: 42}.Encode())
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestHouseFlatsQueryCacheKey(t *testing.T) {
+	roomsMin := 2
+	cursor := HouseFlatsCursor{Sort: HouseFlatsSortPrice, Value: 100000, Id: 7}
+
+	// Тест 1: Одинаковые запросы получают одинаковый ключ
+	first := HouseFlatsQuery{RoomsMin: &roomsMin, Sort: HouseFlatsSortPrice, Limit: 10}
+	second := HouseFlatsQuery{RoomsMin: new(int), Sort: HouseFlatsSortPrice, Limit: 10}
+	*second.RoomsMin = 2
+	assert.Equal(t, first.CacheKey(), second.CacheKey())
+
+	// Тест 2: Следующая страница кэшируется отдельно
+	second.After = &cursor
+	assert.NotEqual(t, first.CacheKey(), second.CacheKey())
+
+	// Тест 3: Курсор восстанавливается после кодирования
+	decoded, err := DecodeHouseFlatsCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
@@ -1,9 +1,40 @@
 package models
 
-import "github.com/golang-jwt/jwt/v4"
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Tokens issued by /dummyLogin belong to these users, they are created
+// together with the tables.
+const (
+	DummyClientId    = `00000000-0000-0000-0000-000000000001`
+	DummyModeratorId = `00000000-0000-0000-0000-000000000002`
+)
+
+const (
+	OutboxEventFlatCreated = `flat_created`
+	OutboxEventFlatUpdated = `flat_updated`
+)
 
 type AuthorizationToken struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshToken struct {
+	Hash      string
+	UserId    string
+	FamilyId  string
+	ExpiresAt time.Time
 }
 
 type CustomClaims struct {
@@ -13,22 +44,92 @@ type CustomClaims struct {
 }
 
 type House struct {
-	Id        int64  `json:"id"`
-	Address   string `json:"address"`
-	Year      int    `json:"year"`
-	Developer string `json:"developer"`
-	CreatedAt string `json:"created_at"`
-	UpdateAt  string `json:"update_at"`
+	Id        int64      `json:"id"`
+	Address   string     `json:"address"`
+	Year      int        `json:"year"`
+	Developer string     `json:"developer"`
+	CreatedAt string     `json:"created_at"`
+	UpdateAt  string     `json:"update_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// HouseEdit holds the house fields a moderator may correct. Nil fields are left as is.
+type HouseEdit struct {
+	Address   *string `json:"address,omitempty"`
+	Year      *int    `json:"year,omitempty"`
+	Developer *string `json:"developer,omitempty"`
+}
+
+func (edit HouseEdit) IsEmpty() bool {
+	return edit.Address == nil && edit.Year == nil && edit.Developer == nil
+}
+
+// HouseFilter narrows the list of houses. Nil and zero fields are not applied.
+// UpdatedSince keeps houses that got a new flat after the given time.
+type HouseFilter struct {
+	Developer    string
+	YearMin      *int
+	YearMax      *int
+	UpdatedSince *time.Time
+	After        *HouseCursor
+	Limit        int
+}
+
+type HousePage struct {
+	Houses     []House `json:"houses"`
+	NextCursor string  `json:"next_cursor,omitempty"`
 }
 
 type Flat struct {
-	Id          int64  `json:"id"`
-	HouseId     int64  `json:"house_id"`
-	Price       int64  `json:"price"`
-	Rooms       int    `json:"rooms"`
-	Status      string `json:"status"`
-	Num         int    `json:"flat_num"`
-	ModeratorId int    `json:"moderator_id"`
+	Id                  int64      `json:"id"`
+	HouseId             int64      `json:"house_id"`
+	Price               int64      `json:"price"`
+	Rooms               int        `json:"rooms"`
+	Status              string     `json:"status"`
+	Num                 int        `json:"flat_num"`
+	ModeratorId         string     `json:"moderator_id,omitempty"`
+	OwnerId             string     `json:"owner_id,omitempty"`
+	ModerationExpiresAt *time.Time `json:"moderation_expires_at,omitempty"`
+	DeclineReasonCode   string     `json:"decline_reason_code,omitempty"`
+	DeclineReason       string     `json:"decline_reason,omitempty"`
+	DeletedAt           *time.Time `json:"deleted_at,omitempty"`
+}
+
+// FlatEvent is the outbox payload of a flat change. OldStatus is the status
+// before the change, it is empty for a new flat.
+type FlatEvent struct {
+	Flat
+	OldStatus string `json:"old_status,omitempty"`
+}
+
+// FlatEdit holds the fields a seller may change. Nil fields are left as is.
+type FlatEdit struct {
+	Price *int64 `json:"price,omitempty"`
+	Rooms *int   `json:"rooms,omitempty"`
+	Num   *int   `json:"flat_num,omitempty"`
+}
+
+func (edit FlatEdit) IsEmpty() bool {
+	return edit.Price == nil && edit.Rooms == nil && edit.Num == nil
+}
+
+// DeclineReason is an entry of the list moderators pick from when declining a flat.
+type DeclineReason struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// FlatStatusChange is a record of the flat status history. OldStatus is empty
+// for the record written when the flat is created.
+type FlatStatusChange struct {
+	Id         int64     `json:"id"`
+	FlatId     int64     `json:"flat_id"`
+	ActorId    string    `json:"actor_id"`
+	OldStatus  string    `json:"old_status,omitempty"`
+	NewStatus  string    `json:"new_status"`
+	ReasonCode string    `json:"reason_code,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type User struct {
@@ -37,3 +138,120 @@ type User struct {
 	Password string `json:"password"`
 	UserType string `json:"user_type"`
 }
+
+type Subscription struct {
+	Id        int64  `json:"id"`
+	HouseId   int64  `json:"house_id"`
+	Email     string `json:"email"`
+	CreatedAt string `json:"created_at"`
+}
+
+type OutboxEvent struct {
+	Id        int64           `json:"id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+}
+
+// ModerationQueueFilter narrows the list of flats awaiting moderation. Nil
+// and zero fields are not applied.
+type ModerationQueueFilter struct {
+	HouseId   int64
+	Developer string
+	PriceMin  *int64
+	PriceMax  *int64
+	After     *FlatCursor
+	Limit     int
+}
+
+type ModerationQueuePage struct {
+	Flats      []Flat `json:"flats"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+const (
+	HouseFlatsSortFlatNum = `flat_num`
+	HouseFlatsSortPrice   = `price`
+	HouseFlatsSortRooms   = `rooms`
+)
+
+// HouseFlatsQuery narrows and orders the flats of one house. Nil fields are
+// not applied, a zero Limit returns all flats.
+type HouseFlatsQuery struct {
+	RoomsMin       *int
+	RoomsMax       *int
+	PriceMin       *int64
+	PriceMax       *int64
+	Sort           string
+	After          *HouseFlatsCursor
+	Limit          int
+	IncludeDeleted bool
+}
+
+// CacheKey identifies the query shape. Queries that differ only in how the
+// parameters were written share the key.
+func (query HouseFlatsQuery) CacheKey() string {
+	values := url.Values{`sort`: {query.Sort}}
+
+	if query.RoomsMin != nil {
+		values.Set(`rooms_min`, strconv.Itoa(*query.RoomsMin))
+	}
+
+	if query.RoomsMax != nil {
+		values.Set(`rooms_max`, strconv.Itoa(*query.RoomsMax))
+	}
+
+	if query.PriceMin != nil {
+		values.Set(`price_min`, strconv.FormatInt(*query.PriceMin, 10))
+	}
+
+	if query.PriceMax != nil {
+		values.Set(`price_max`, strconv.FormatInt(*query.PriceMax, 10))
+	}
+
+	if query.After != nil {
+		values.Set(`cursor`, query.After.Encode())
+	}
+
+	if query.Limit != 0 {
+		values.Set(`limit`, strconv.Itoa(query.Limit))
+	}
+
+	if query.IncludeDeleted {
+		values.Set(`include_deleted`, `true`)
+	}
+
+	return values.Encode()
+}
+
+type HouseFlatsPage struct {
+	Flats      []Flat `json:"flats"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+const (
+	FlatSortNewest    = `newest`
+	FlatSortPriceAsc  = `price_asc`
+	FlatSortPriceDesc = `price_desc`
+)
+
+// FlatSearchFilter narrows the flat search across all houses. Nil and zero
+// fields are not applied. Address is matched as a substring of the house address.
+type FlatSearchFilter struct {
+	PriceMin  *int64
+	PriceMax  *int64
+	RoomsMin  *int
+	RoomsMax  *int
+	YearMin   *int
+	YearMax   *int
+	Developer string
+	Address   string
+	Sort      string
+	After     *FlatSearchCursor
+	Limit     int
+}
+
+type FlatSearchPage struct {
+	Flats      []Flat `json:"flats"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,62 @@
+package models
+
+const (
+	StatusCreated      = `created`
+	StatusOnModeration = `on moderation`
+	StatusApproved     = `approved`
+	StatusDeclined     = `declined`
+)
+
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusCreated, StatusOnModeration, StatusApproved, StatusDeclined:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReopenRules enable status changes that take a moderated flat back into
+// the moderation flow. All of them are disabled by default.
+type ReopenRules struct {
+	DeclinedToCreated      bool
+	DeclinedToOnModeration bool
+	ApprovedToOnModeration bool
+}
+
+// StatusTransitions maps a flat status to the statuses it may be changed to.
+type StatusTransitions map[string][]string
+
+// NewStatusTransitions builds the flat lifecycle:
+// created -> on moderation -> approved | declined, plus the enabled reopen rules.
+// A flat on moderation may also be released back to created.
+func NewStatusTransitions(reopen ReopenRules) StatusTransitions {
+	transitions := StatusTransitions{
+		StatusCreated:      {StatusOnModeration},
+		StatusOnModeration: {StatusApproved, StatusDeclined, StatusCreated},
+	}
+
+	if reopen.DeclinedToCreated {
+		transitions[StatusDeclined] = append(transitions[StatusDeclined], StatusCreated)
+	}
+
+	if reopen.DeclinedToOnModeration {
+		transitions[StatusDeclined] = append(transitions[StatusDeclined], StatusOnModeration)
+	}
+
+	if reopen.ApprovedToOnModeration {
+		transitions[StatusApproved] = append(transitions[StatusApproved], StatusOnModeration)
+	}
+
+	return transitions
+}
+
+func (transitions StatusTransitions) Allowed(from string, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusTransitions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		reopen   ReopenRules
+		from     string
+		to       string
+		expected bool
+	}{
+		{name: "Take for moderation", from: StatusCreated, to: StatusOnModeration, expected: true},
+		{name: "Approve after moderation", from: StatusOnModeration, to: StatusApproved, expected: true},
+		{name: "Decline after moderation", from: StatusOnModeration, to: StatusDeclined, expected: true},
+		{name: "Release after moderation", from: StatusOnModeration, to: StatusCreated, expected: true},
+		{name: "Approve without moderation", from: StatusCreated, to: StatusApproved, expected: false},
+		{name: "Declined back to created is disabled by default", from: StatusDeclined, to: StatusCreated, expected: false},
+		{name: "Declined back to created", reopen: ReopenRules{DeclinedToCreated: true}, from: StatusDeclined, to: StatusCreated, expected: true},
+		{name: "Approved back to moderation", reopen: ReopenRules{ApprovedToOnModeration: true}, from: StatusApproved, to: StatusOnModeration, expected: true},
+		{name: "Approved to declined is never allowed", reopen: ReopenRules{ApprovedToOnModeration: true}, from: StatusApproved, to: StatusDeclined, expected: false},
+		{name: "Same status", from: StatusOnModeration, to: StatusOnModeration, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewStatusTransitions(tc.reopen).Allowed(tc.from, tc.to))
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"avitoBootcamp/internal/models"
+	"avitoBootcamp/internal/sender"
+	"avitoBootcamp/internal/storage"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = time.Second
+	defaultLease        = time.Minute
+	defaultBaseBackoff  = time.Second
+	defaultMaxBackoff   = 10 * time.Minute
+)
+
+// Worker drains the outbox table. Events are never dropped: a failed event is
+// rescheduled with exponential backoff until its delivery succeeds.
+type Worker struct {
+	db           storage.Database
+	cache        storage.Cache
+	sender       sender.Sender
+	batchSize    int
+	pollInterval time.Duration
+	lease        time.Duration
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+}
+
+func NewWorker(db storage.Database, cache storage.Cache, emailSender sender.Sender) *Worker {
+	return &Worker{
+		db:           db,
+		cache:        cache,
+		sender:       emailSender,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+		lease:        defaultLease,
+		baseBackoff:  defaultBaseBackoff,
+		maxBackoff:   defaultMaxBackoff,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for w.ProcessBatch(ctx) == w.batchSize {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch claims and handles one batch of events and returns its size.
+func (w *Worker) ProcessBatch(ctx context.Context) int {
+	events, err := w.db.ClaimOutboxEvents(ctx, w.batchSize, w.lease)
+
+	if err != nil {
+		slog.Error("Failed to claim outbox events", "error", err)
+		return 0
+	}
+
+	for _, event := range events {
+		if err := w.handle(ctx, event); err != nil {
+			nextAttemptAt := time.Now().Add(w.backoff(event.Attempts))
+			slog.Error("Failed to handle outbox event", "id", event.Id, "type", event.EventType, "attempts", event.Attempts, "nextAttemptAt", nextAttemptAt, "error", err)
+
+			if err := w.db.FailOutboxEvent(ctx, event.Id, nextAttemptAt, err.Error()); err != nil {
+				slog.Error("Failed to reschedule outbox event", "id", event.Id, "error", err)
+			}
+
+			continue
+		}
+
+		if err := w.db.CompleteOutboxEvent(ctx, event.Id); err != nil {
+			slog.Error("Failed to complete outbox event", "id", event.Id, "error", err)
+		}
+	}
+
+	return len(events)
+}
+
+func (w *Worker) backoff(attempts int) time.Duration {
+	backoff := w.baseBackoff
+
+	for i := 1; i < attempts && backoff < w.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, w.maxBackoff)
+}
+
+func (w *Worker) handle(ctx context.Context, event models.OutboxEvent) error {
+	switch event.EventType {
+	case models.OutboxEventFlatCreated, models.OutboxEventFlatUpdated:
+		var flatEvent models.FlatEvent
+		if err := json.Unmarshal(event.Payload, &flatEvent); err != nil {
+			return err
+		}
+
+		flat := flatEvent.Flat
+
+		// Clients see approved flats only, so their lists change when a flat
+		// becomes approved and when it stops being approved.
+		w.cache.DeleteFlatsByHouseId(ctx, flat.HouseId, `moderator`)
+		if flat.Status == `approved` || flatEvent.OldStatus == `approved` {
+			w.cache.DeleteFlatsByHouseId(ctx, flat.HouseId, `client`)
+		}
+
+		if event.EventType == models.OutboxEventFlatUpdated && flat.Status == `approved` {
+			return w.notifySubscribers(ctx, flat)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown outbox event type %q", event.EventType)
+	}
+}
+
+func (w *Worker) notifySubscribers(ctx context.Context, flat models.Flat) error {
+	subscriptions, err := w.db.GetSubscriptionsByHouseID(ctx, flat.HouseId)
+
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf(`A new flat №%d is available in house %d: %d rooms, price %d`, flat.Num, flat.HouseId, flat.Rooms, flat.Price)
+
+	for _, subscription := range subscriptions {
+		if err := w.sender.SendEmail(ctx, subscription.Email, message); err != nil {
+			return fmt.Errorf("send email to %s: %w", subscription.Email, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package outbox
+
+import (
+	"avitoBootcamp/internal/models"
+	"avitoBootcamp/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeSender struct {
+	sent []string
+	err  error
+}
+
+func (s *fakeSender) SendEmail(ctx context.Context, recipient string, message string) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.sent = append(s.sent, recipient)
+
+	return nil
+}
+
+func TestProcessBatch(t *testing.T) {
+	approvedFlat, _ := json.Marshal(models.Flat{Id: 1, HouseId: 10, Price: 100000, Rooms: 3, Num: 101, Status: "approved"})
+	createdFlat, _ := json.Marshal(models.Flat{Id: 2, HouseId: 10, Price: 100000, Rooms: 3, Num: 102, Status: "created"})
+	reopenedFlat, _ := json.Marshal(models.FlatEvent{Flat: models.Flat{Id: 3, HouseId: 10, Price: 100000, Rooms: 3, Num: 103, Status: "on moderation"}, OldStatus: "approved"})
+
+	testCases := []struct {
+		name              string
+		event             models.OutboxEvent
+		senderErr         error
+		expectSent        []string
+		expectClientClear bool
+		expectNotify      bool
+		expectSuccess     bool
+	}{
+		// Тест 1: Квартира одобрена, подписчики получают письма
+		{
+			name:              "Approved flat notifies subscribers",
+			event:             models.OutboxEvent{Id: 1, EventType: models.OutboxEventFlatUpdated, Payload: approvedFlat, Attempts: 1},
+			expectSent:        []string{"first@gmail.com", "second@gmail.com"},
+			expectClientClear: true,
+			expectNotify:      true,
+			expectSuccess:     true,
+		},
+		// Тест 2: Новая квартира, уведомления не отправляются
+		{
+			name:          "Created flat only invalidates cache",
+			event:         models.OutboxEvent{Id: 2, EventType: models.OutboxEventFlatCreated, Payload: createdFlat, Attempts: 1},
+			expectSuccess: true,
+		},
+		// Тест 3: Ошибка отправки, событие откладывается
+		{
+			name:              "Sender failure reschedules event",
+			event:             models.OutboxEvent{Id: 3, EventType: models.OutboxEventFlatUpdated, Payload: approvedFlat, Attempts: 3},
+			senderErr:         errors.New("smtp is down"),
+			expectClientClear: true,
+			expectNotify:      true,
+			expectSuccess:     false,
+		},
+		// Тест 4: Одобренная квартира возвращена на модерацию, кэш клиентов сбрасывается
+		{
+			name:              "Reopened flat invalidates client cache",
+			event:             models.OutboxEvent{Id: 4, EventType: models.OutboxEventFlatUpdated, Payload: reopenedFlat, Attempts: 1},
+			expectClientClear: true,
+			expectSuccess:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			emailSender := &fakeSender{err: tc.senderErr}
+
+			worker := NewWorker(mockDB, mockCache, emailSender)
+
+			mockDB.On("ClaimOutboxEvents", mock.Anything, worker.batchSize, worker.lease).Return([]models.OutboxEvent{tc.event}, nil).Once()
+			mockCache.On("DeleteFlatsByHouseId", mock.Anything, int64(10), "moderator").Once()
+
+			if tc.expectClientClear {
+				mockCache.On("DeleteFlatsByHouseId", mock.Anything, int64(10), "client").Once()
+			}
+
+			if tc.expectNotify {
+				mockDB.On("GetSubscriptionsByHouseID", mock.Anything, int64(10)).Return([]models.Subscription{
+					{Id: 1, HouseId: 10, Email: "first@gmail.com"},
+					{Id: 2, HouseId: 10, Email: "second@gmail.com"},
+				}, nil).Once()
+			}
+
+			before := time.Now()
+			if tc.expectSuccess {
+				mockDB.On("CompleteOutboxEvent", mock.Anything, tc.event.Id).Return(nil).Once()
+			} else {
+				mockDB.On("FailOutboxEvent", mock.Anything, tc.event.Id, mock.MatchedBy(func(next time.Time) bool {
+					return !next.Before(before.Add(worker.backoff(tc.event.Attempts)))
+				}), mock.Anything).Return(nil).Once()
+			}
+
+			assert.Equal(t, 1, worker.ProcessBatch(context.Background()))
+			assert.Equal(t, tc.expectSent, emailSender.sent)
+
+			mockDB.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	worker := NewWorker(nil, nil, nil)
+
+	assert.Equal(t, time.Second, worker.backoff(1))
+	assert.Equal(t, 2*time.Second, worker.backoff(2))
+	assert.Equal(t, 8*time.Second, worker.backoff(4))
+	assert.Equal(t, worker.maxBackoff, worker.backoff(100))
+}
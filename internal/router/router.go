@@ -1,58 +1,72 @@
 package router
 
 import (
+	"avitoBootcamp/internal/api"
+	"avitoBootcamp/internal/apierror"
+	"avitoBootcamp/internal/auth"
+	"avitoBootcamp/internal/config"
 	"avitoBootcamp/internal/handlers"
-	"avitoBootcamp/internal/models"
 	"avitoBootcamp/internal/storage"
-	"encoding/json"
+	"avitoBootcamp/internal/validation"
 	"fmt"
 	"net/http"
-	"net/http/httptest"
+	"sync"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 )
 
-func New(database storage.Database, cache storage.Cache) http.Handler {
-	router := mux.NewRouter()
+// specValidator is built once, the spec is embedded and never changes.
+var specValidator = sync.OnceValue(func() *validation.Validator {
+	spec, err := api.GetSwagger()
+	if err != nil {
+		panic(fmt.Sprintf(`invalid api.yaml: %v`, err))
+	}
 
-	router.HandleFunc(`/dummyLogin`, handlers.DummyLoginHandler).Methods(`GET`)
-	router.Handle(`/login`, handlers.LoginHandler(database)).Methods(`POST`)
-	router.Handle(`/register`, handlers.RegisterHandler(database)).Methods(`POST`)
-	router.Handle(`/house/{id}`, handlers.AuthorizationMiddleware(handlers.GetFlatsInHouseHandler(database, cache), false, database)).Methods(`GET`)
-	router.Handle(`/flat/create`, handlers.AuthorizationMiddleware(handlers.FlatCreateHandler(database, cache), false, database)).Methods(`POST`)
-	router.Handle(`/house/create`, handlers.AuthorizationMiddleware(handlers.HouseCreateHandler(database), true, database)).Methods(`POST`)
-	router.Handle(`/flat/update`, handlers.AuthorizationMiddleware(handlers.FlatUpdateHandler(database, cache), true, database)).Methods(`POST`)
+	validator, err := validation.New(spec)
+	if err != nil {
+		panic(fmt.Sprintf(`invalid api.yaml: %v`, err))
+	}
+
+	return validator
+})
 
+func New(database storage.Database, cache storage.Cache, keys *auth.Keyring, ttl config.Auth) http.Handler {
 	handler := cors.New(cors.Options{
 		AllowedOrigins:   []string{`*`},
 		AllowedMethods:   []string{`GET`, `POST`, `DELETE`, `OPTIONS`, `PATCH`, `PUT`},
-		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Request-Id"},
+		ExposedHeaders:   []string{"X-Request-Id"},
 		AllowCredentials: true,
-	}).Handler(router)
+	}).Handler(handlers.RequestIdMiddleware(newRouter(database, cache, keys, ttl)))
 
 	return handler
 }
 
-func PerformLogin(userType string) (string, error) {
-	loginReq, err := http.NewRequest("GET", "/dummyLogin?user_type="+userType, nil)
-	if err != nil {
-		return "", err
-	}
+// newRouter serves the operations of api.yaml, the routes and the request
+// decoding are generated into the api package.
+func newRouter(database storage.Database, cache storage.Cache, keys *auth.Keyring, ttl config.Auth) *mux.Router {
+	router := mux.NewRouter()
+	router.NotFoundHandler = handlers.NotFoundHandler()
+	router.MethodNotAllowedHandler = handlers.MethodNotAllowedHandler()
+	router.Use(specValidator().Middleware)
 
-	loginRR := httptest.NewRecorder()
-	loginHandler := http.HandlerFunc(handlers.DummyLoginHandler)
-	loginHandler.ServeHTTP(loginRR, loginReq)
+	server := api.NewStrictHandlerWithOptions(handlers.NewServer(database, cache, keys, ttl), nil, api.StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  writeRequestError,
+		ResponseErrorHandlerFunc: apierror.Write,
+	})
 
-	if loginRR.Code != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", loginRR.Code)
-	}
+	api.HandlerWithOptions(server, api.GorillaServerOptions{
+		BaseRouter:       router,
+		Middlewares:      []api.MiddlewareFunc{handlers.AuthorizationMiddleware(database, cache, keys)},
+		ErrorHandlerFunc: writeRequestError,
+	})
 
-	var tokenResponse models.AuthorizationToken
-	err = json.Unmarshal(loginRR.Body.Bytes(), &tokenResponse)
-	if err != nil {
-		return "", err
-	}
+	return router
+}
 
-	return `Bearer ` + tokenResponse.Token, nil
+// writeRequestError answers requests whose parameters or body could not be
+// decoded. The spec validation usually rejects them first.
+func writeRequestError(w http.ResponseWriter, r *http.Request, err error) {
+	apierror.Write(w, r, apierror.BadRequest(err.Error()))
 }
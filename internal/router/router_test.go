@@ -1,20 +1,54 @@
 package router
 
 import (
+	"avitoBootcamp/internal/apierror"
+	"avitoBootcamp/internal/auth"
+	"avitoBootcamp/internal/client"
+	"avitoBootcamp/internal/config"
 	"avitoBootcamp/internal/models"
+	"avitoBootcamp/internal/storage"
 	"avitoBootcamp/internal/storage/mocks"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	testKeys, _ = auth.NewKeyring(auth.NewHMACKey(`test`, []byte(`test-secret-test-secret-test-secret`)))
+	testConfig  = config.Default()
+)
+
+// performLogin gets a dummy token through the typed client and returns it as
+// an Authorization header value.
+func performLogin(userType string) (string, error) {
+	server := httptest.NewServer(New(nil, nil, testKeys, testConfig.Auth))
+	defer server.Close()
+
+	c, err := client.NewClientWithResponses(server.URL)
+	if err != nil {
+		return ``, err
+	}
+
+	token, err := c.DummyToken(context.Background(), client.UserType(userType))
+	if err != nil {
+		return ``, err
+	}
+
+	return `Bearer ` + token, nil
+}
+
 func TestGetFlatsInHouseHandler(t *testing.T) {
 
 	testCases := []struct {
@@ -29,8 +63,8 @@ func TestGetFlatsInHouseHandler(t *testing.T) {
 			houseId:  1,
 			userType: `client`,
 			expectedFlats: []models.Flat{
-				{Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "created", ModeratorId: 15},
-				{Id: 13, HouseId: 100, Price: 250000, Rooms: 4, Num: 12, Status: "approved", ModeratorId: 7},
+				{Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "created", ModeratorId: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c15"},
+				{Id: 13, HouseId: 100, Price: 250000, Rooms: 4, Num: 12, Status: "approved", ModeratorId: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c07"},
 			},
 			authorized:     true,
 			expectCacheHit: false,
@@ -40,8 +74,8 @@ func TestGetFlatsInHouseHandler(t *testing.T) {
 			houseId:  2,
 			userType: `moderator`,
 			expectedFlats: []models.Flat{
-				{Id: 14, HouseId: 200, Price: 300000, Rooms: 5, Num: 20, Status: "on moderation", ModeratorId: 22},
-				{Id: 15, HouseId: 200, Price: 350000, Rooms: 6, Num: 25, Status: "declined", ModeratorId: 33},
+				{Id: 14, HouseId: 200, Price: 300000, Rooms: 5, Num: 20, Status: "on moderation", ModeratorId: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c22"},
+				{Id: 15, HouseId: 200, Price: 350000, Rooms: 6, Num: 25, Status: "declined", ModeratorId: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c33"},
 			},
 			authorized:     true,
 			expectCacheHit: true,
@@ -67,8 +101,8 @@ func TestGetFlatsInHouseHandler(t *testing.T) {
 			houseId:  4,
 			userType: `client`,
 			expectedFlats: []models.Flat{
-				{Id: 16, HouseId: 300, Price: 400000, Rooms: 7, Num: 30, Status: "approved", ModeratorId: 44},
-				{Id: 17, HouseId: 300, Price: 450000, Rooms: 8, Num: 35, Status: "created", ModeratorId: 55},
+				{Id: 16, HouseId: 300, Price: 400000, Rooms: 7, Num: 30, Status: "approved", ModeratorId: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c44"},
+				{Id: 17, HouseId: 300, Price: 450000, Rooms: 8, Num: 35, Status: "created", ModeratorId: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c55"},
 			},
 			authorized:     true,
 			expectCacheHit: false,
@@ -79,26 +113,30 @@ func TestGetFlatsInHouseHandler(t *testing.T) {
 		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.userType), func(t *testing.T) {
 			mockDB := new(mocks.Database)
 			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			query := models.HouseFlatsQuery{Sort: models.HouseFlatsSortFlatNum}
+			page := models.HouseFlatsPage{Flats: tc.expectedFlats}
 
 			if tc.expectCacheHit {
-				cachedData, _ := json.Marshal(tc.expectedFlats)
-				mockCache.On("GetFlatsByHouseID", tc.houseId, tc.userType).Return(cachedData, nil).Once()
+				cachedData, _ := json.Marshal(page)
+				mockCache.On("GetFlatsByHouseID", mock.Anything, tc.houseId, tc.userType, query.CacheKey()).Return(cachedData, nil).Once()
 			} else {
-				mockCache.On("GetFlatsByHouseID", tc.houseId, tc.userType).Return(nil, redis.Nil).Once()
-				mockDB.On("GetFlatsByHouseID", tc.houseId, tc.userType).Return(tc.expectedFlats, nil).Once()
-				mockCache.On("PutFlatsByHouseID", tc.expectedFlats, tc.houseId, tc.userType).Return(nil).Once()
+				mockCache.On("GetFlatsByHouseID", mock.Anything, tc.houseId, tc.userType, query.CacheKey()).Return(nil, redis.Nil).Once()
+				mockDB.On("GetFlatsByHouseID", mock.Anything, tc.houseId, tc.userType, query).Return(page, nil).Once()
+				mockCache.On("PutFlatsByHouseID", mock.Anything, page, tc.houseId, tc.userType, query.CacheKey()).Return(nil).Once()
 			}
 
 			var token string
 			if tc.authorized {
-				token, _ = PerformLogin(tc.userType)
+				token, _ = performLogin(tc.userType)
 			}
 			req, err := http.NewRequest("GET", fmt.Sprintf("/house/%d", tc.houseId), nil)
 			assert.NoError(t, err)
 			req.Header.Set("Authorization", token)
 
 			rr := httptest.NewRecorder()
-			handler := New(mockDB, mockCache)
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
 			handler.ServeHTTP(rr, req)
 
 			if !tc.authorized && rr.Code == http.StatusUnauthorized {
@@ -109,10 +147,10 @@ func TestGetFlatsInHouseHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, rr.Code)
 			assert.NotEmpty(t, rr.Body.Bytes(), "Response body should not be empty")
 
-			var flats []models.Flat
-			err = json.Unmarshal(rr.Body.Bytes(), &flats)
+			var response models.HouseFlatsPage
+			err = json.Unmarshal(rr.Body.Bytes(), &response)
 			assert.NoError(t, err)
-			assert.Equal(t, tc.expectedFlats, flats)
+			assert.Equal(t, page, response)
 
 			mockDB.AssertExpectations(t)
 			mockCache.AssertExpectations(t)
@@ -121,6 +159,110 @@ func TestGetFlatsInHouseHandler(t *testing.T) {
 	}
 }
 
+func TestGetFlatsInHousePaging(t *testing.T) {
+	roomsMin := 2
+	priceMax := int64(300000)
+	cursor := models.HouseFlatsCursor{Sort: models.HouseFlatsSortPrice, Value: 199000, Id: 12}
+	nextCursor := models.HouseFlatsCursor{Sort: models.HouseFlatsSortPrice, Value: 250000, Id: 13}
+	page := models.HouseFlatsPage{
+		Flats:      []models.Flat{{Id: 13, HouseId: 1, Price: 250000, Rooms: 4, Num: 12, Status: "approved"}},
+		NextCursor: nextCursor.Encode(),
+	}
+
+	testCases := []struct {
+		name          string
+		userType      string
+		query         string
+		expectedQuery *models.HouseFlatsQuery
+		cached        bool
+		expectedCode  int
+	}{
+		// Тест 1: Фильтры, сортировка и курсор передаются в базу данных, страница кэшируется
+		{
+			name:     "Filters, sort and cursor",
+			userType: "client",
+			query:    "?rooms_min=2&price_max=300000&sort=price&limit=1&cursor=" + cursor.Encode(),
+			expectedQuery: &models.HouseFlatsQuery{
+				RoomsMin: &roomsMin,
+				PriceMax: &priceMax,
+				Sort:     models.HouseFlatsSortPrice,
+				After:    &cursor,
+				Limit:    1,
+			},
+			cached:       true,
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Квартиры в архиве не кэшируются
+		{
+			name:          "Include deleted",
+			userType:      "moderator",
+			query:         "?include_deleted=true&sort=price&limit=1",
+			expectedQuery: &models.HouseFlatsQuery{Sort: models.HouseFlatsSortPrice, Limit: 1, IncludeDeleted: true},
+			expectedCode:  http.StatusOK,
+		},
+		// Тест 3: Курсор выдан для другой сортировки
+		{
+			name:         "Cursor of another sort",
+			userType:     "client",
+			query:        "?sort=rooms&cursor=" + cursor.Encode(),
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 4: Неизвестная сортировка
+		{
+			name:         "Invalid sort",
+			userType:     "client",
+			query:        "?sort=status",
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 5: Слишком большая страница
+		{
+			name:         "Invalid limit",
+			userType:     "client",
+			query:        "?limit=1000",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			if tc.expectedQuery != nil {
+				mockDB.On("GetFlatsByHouseID", mock.Anything, int64(1), tc.userType, *tc.expectedQuery).Return(page, nil).Once()
+			}
+
+			if tc.cached {
+				mockCache.On("GetFlatsByHouseID", mock.Anything, int64(1), tc.userType, tc.expectedQuery.CacheKey()).Return(nil, redis.Nil).Once()
+				mockCache.On("PutFlatsByHouseID", mock.Anything, page, int64(1), tc.userType, tc.expectedQuery.CacheKey()).Return(nil).Once()
+			}
+
+			token, _ := performLogin(tc.userType)
+
+			req, err := http.NewRequest("GET", "/house/1"+tc.query, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var response models.HouseFlatsPage
+				err = json.Unmarshal(rr.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, page, response)
+			}
+
+			mockDB.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
 func TestFlatCreateHandler(t *testing.T) {
 
 	testCases := []struct {
@@ -177,19 +319,20 @@ func TestFlatCreateHandler(t *testing.T) {
 		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.inputFlat.Status), func(t *testing.T) {
 			mockDB := new(mocks.Database)
 			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
 
-			mockDB.On("CreateFlat", tc.inputFlat).Return(tc.expectedFlat, nil).Once()
+			expectedInput := tc.inputFlat
+			expectedInput.OwnerId = models.DummyModeratorId
+			mockDB.On("CreateFlat", mock.Anything, expectedInput).Return(tc.expectedFlat, nil).Once()
 
-			mockCache.On("DeleteFlatsByHouseId", tc.inputFlat.HouseId, "moderator").Once()
+			mockCache.On("DeleteFlatsByHouseId", mock.Anything, tc.inputFlat.HouseId, "moderator").Once()
 			if tc.expectedFlat.Status == "approved" {
-				mockCache.On("DeleteFlatsByHouseId", tc.inputFlat.HouseId, "client").Once()
+				mockCache.On("DeleteFlatsByHouseId", mock.Anything, tc.inputFlat.HouseId, "client").Once()
 			}
 
-			mockDB.On("UpdateAtHouseLastFlatTime", tc.inputFlat.HouseId).Return(nil).Once()
-
 			var token string
 			if tc.authorized {
-				token, _ = PerformLogin("moderator")
+				token, _ = performLogin("moderator")
 			}
 			reqBody, _ := json.Marshal(tc.inputFlat)
 			req, err := http.NewRequest("POST", "/flat/create", bytes.NewBuffer(reqBody))
@@ -198,7 +341,7 @@ func TestFlatCreateHandler(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
-			handler := New(mockDB, mockCache)
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
 			handler.ServeHTTP(rr, req)
 
 			if !tc.authorized && rr.Code == http.StatusUnauthorized {
@@ -220,6 +363,61 @@ func TestFlatCreateHandler(t *testing.T) {
 	}
 }
 
+func TestFlatCreateErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		dbError       error
+		expectedCode  int
+		expectedError int
+	}{
+		// Тест 1: Дом не существует
+		{
+			name:          "House not found",
+			dbError:       storage.ErrHouseNotFound,
+			expectedCode:  http.StatusNotFound,
+			expectedError: apierror.CodeHouseNotFound,
+		},
+		// Тест 2: Номер квартиры уже занят в доме
+		{
+			name:          "Flat number taken",
+			dbError:       storage.ErrFlatNumberTaken,
+			expectedCode:  http.StatusConflict,
+			expectedError: apierror.CodeFlatNumberTaken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			flat := models.Flat{HouseId: 1000, Price: 100000, Rooms: 3, Num: 10, OwnerId: models.DummyModeratorId}
+			mockDB.On("CreateFlat", mock.Anything, flat).Return(models.Flat{}, tc.dbError).Once()
+
+			token, _ := performLogin("moderator")
+
+			req, err := http.NewRequest("POST", "/flat/create", bytes.NewBufferString(`{"house_id": 1000, "price": 100000, "rooms": 3, "flat_num": 10}`))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			var response apierror.Response
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tc.expectedError, response.Code)
+
+			mockDB.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
 func TestHouseCreateHandler(t *testing.T) {
 
 	testCases := []struct {
@@ -280,16 +478,17 @@ func TestHouseCreateHandler(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			mockDB := new(mocks.Database)
 			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
 
 			if tc.expectedCode == http.StatusOK {
-				mockDB.On("CreateHouse", tc.inputHouse).Return(tc.expectedHouse, nil).Once()
+				mockDB.On("CreateHouse", mock.Anything, tc.inputHouse).Return(tc.expectedHouse, nil).Once()
 			} else if tc.expectedCode == http.StatusInternalServerError {
-				mockDB.On("CreateHouse", tc.inputHouse).Return(models.House{}, errors.New("database error")).Once()
+				mockDB.On("CreateHouse", mock.Anything, tc.inputHouse).Return(models.House{}, errors.New("database error")).Once()
 			}
 
 			var token string
 			if tc.authorized {
-				token, _ = PerformLogin("moderator")
+				token, _ = performLogin("moderator")
 			}
 
 			var body []byte
@@ -305,12 +504,12 @@ func TestHouseCreateHandler(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
-			handler := New(mockDB, mockCache)
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
 			handler.ServeHTTP(rr, req)
 
 			if !tc.authorized {
 				assert.Equal(t, http.StatusUnauthorized, rr.Code)
-				mockDB.AssertNotCalled(t, "CreateHouse", tc.inputHouse)
+				mockDB.AssertNotCalled(t, "CreateHouse", mock.Anything, tc.inputHouse)
 				return
 			}
 
@@ -337,21 +536,35 @@ func TestFlatUpdateHandler(t *testing.T) {
 		authorized       bool
 		expectedCode     int
 		expectCacheClear bool
+		dbError          error
 	}{
 		// Тест 1: Успешное обновление квартиры, авторизован
 		{
 			name: "Successful update by moderator",
 			inputFlat: models.Flat{
-				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "created", ModeratorId: 15,
+				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "created", ModeratorId: models.DummyModeratorId,
+			},
+			updatedFlat: models.Flat{
+				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "approved", ModeratorId: models.DummyModeratorId,
+			},
+			authorized:       true,
+			expectedCode:     http.StatusOK,
+			expectCacheClear: true,
+		},
+		// Тест 2: Идентификатор модератора берется из токена, а не из тела запроса
+		{
+			name: "Moderator id from body is ignored",
+			inputFlat: models.Flat{
+				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "on moderation", ModeratorId: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c15",
 			},
 			updatedFlat: models.Flat{
-				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "approved", ModeratorId: 15,
+				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "on moderation", ModeratorId: models.DummyModeratorId,
 			},
 			authorized:       true,
 			expectedCode:     http.StatusOK,
 			expectCacheClear: true,
 		},
-		// Тест 2: Ошибка десериализации JSON
+		// Тест 3: Ошибка десериализации JSON
 		{
 			name:             "JSON Unmarshal error",
 			inputFlat:        models.Flat{},
@@ -360,52 +573,143 @@ func TestFlatUpdateHandler(t *testing.T) {
 			expectedCode:     http.StatusBadRequest,
 			expectCacheClear: false,
 		},
-		// Тест 3: Неавторизованный запрос
+		// Тест 4: Неавторизованный запрос
 		{
 			name: "Unauthorized access",
 			inputFlat: models.Flat{
-				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "created", ModeratorId: 15,
+				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "created", ModeratorId: models.DummyModeratorId,
 			},
 			updatedFlat:  models.Flat{},
 			authorized:   false,
 			expectedCode: http.StatusUnauthorized,
 		},
-		// Тест 4: Ошибка обновления базы данных
+		// Тест 5: Ошибка обновления базы данных
 		{
 			name: "Database update error",
 			inputFlat: models.Flat{
-				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "created", ModeratorId: 15,
+				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "created", ModeratorId: models.DummyModeratorId,
 			},
 			updatedFlat:  models.Flat{},
 			authorized:   true,
 			expectedCode: http.StatusInternalServerError,
 		},
+		// Тест 6: Неизвестный статус отклоняется до обращения к базе данных
+		{
+			name: "Unknown status",
+			inputFlat: models.Flat{
+				Id: 12, Status: "sold",
+			},
+			authorized:   true,
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 7: Недопустимый переход статуса
+		{
+			name: "Forbidden status transition",
+			inputFlat: models.Flat{
+				Id: 12, Status: "approved",
+			},
+			authorized:   true,
+			expectedCode: http.StatusConflict,
+			dbError:      storage.ErrInvalidStatusTransition,
+		},
+		// Тест 8: Квартира на модерации у другого модератора
+		{
+			name: "Flat locked by another moderator",
+			inputFlat: models.Flat{
+				Id: 12, Status: "declined", DeclineReasonCode: "other", DeclineReason: "Нет фотографий",
+			},
+			authorized:   true,
+			expectedCode: http.StatusConflict,
+			dbError:      storage.ErrFlatLocked,
+		},
+		// Тест 9: Квартира не найдена
+		{
+			name: "Flat not found",
+			inputFlat: models.Flat{
+				Id: 9999, Status: "approved",
+			},
+			authorized:   true,
+			expectedCode: http.StatusNotFound,
+			dbError:      storage.ErrNotFound,
+		},
+		// Тест 10: Причина отклонения передается в базу данных
+		{
+			name: "Decline with reason",
+			inputFlat: models.Flat{
+				Id: 12, HouseId: 100, Status: "declined", DeclineReasonCode: "wrong_price", DeclineReason: "Цена завышена",
+			},
+			updatedFlat: models.Flat{
+				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "declined", ModeratorId: models.DummyModeratorId,
+				DeclineReasonCode: "wrong_price", DeclineReason: "Цена завышена",
+			},
+			authorized:       true,
+			expectedCode:     http.StatusOK,
+			expectCacheClear: true,
+		},
+		// Тест 11: Отклонение без причины
+		{
+			name: "Decline without reason",
+			inputFlat: models.Flat{
+				Id: 12, Status: "declined", DeclineReasonCode: "wrong_price",
+			},
+			authorized:   true,
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 12: Причины нет в списке
+		{
+			name: "Unknown decline reason",
+			inputFlat: models.Flat{
+				Id: 12, Status: "declined", DeclineReasonCode: "bad_mood", DeclineReason: "Плохое настроение",
+			},
+			authorized:   true,
+			expectedCode: http.StatusBadRequest,
+			dbError:      storage.ErrInvalidDeclineReason,
+		},
+		// Тест 13: Одобренная квартира возвращается на модерацию и пропадает из кэша клиентов
+		{
+			name: "Approved flat reopened",
+			inputFlat: models.Flat{
+				Id: 12, HouseId: 100, Status: "on moderation",
+			},
+			updatedFlat: models.Flat{
+				Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "on moderation", ModeratorId: models.DummyModeratorId,
+			},
+			authorized:       true,
+			expectedCode:     http.StatusOK,
+			expectCacheClear: true,
+		},
 	}
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
 			mockDB := new(mocks.Database)
 			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			expectedInput := tc.inputFlat
+			expectedInput.ModeratorId = models.DummyModeratorId
 
 			if tc.expectedCode == http.StatusOK {
-				mockDB.On("UpdateFlat", tc.inputFlat).Return(tc.updatedFlat, nil).Once()
+				mockDB.On("UpdateFlat", mock.Anything, expectedInput).Return(tc.updatedFlat, nil).Once()
 				if tc.expectCacheClear {
-					mockCache.On("DeleteFlatsByHouseId", tc.inputFlat.HouseId, "moderator").Return(nil).Once()
-					if tc.updatedFlat.Status == "approved" {
-						mockCache.On("DeleteFlatsByHouseId", tc.inputFlat.HouseId, "client").Return(nil).Once()
+					mockCache.On("DeleteFlatsByHouseId", mock.Anything, tc.inputFlat.HouseId, "moderator").Return(nil).Once()
+					if tc.updatedFlat.Status == "approved" || tc.updatedFlat.Status == "on moderation" {
+						mockCache.On("DeleteFlatsByHouseId", mock.Anything, tc.inputFlat.HouseId, "client").Return(nil).Once()
 					}
 				}
 			} else if tc.expectedCode == http.StatusInternalServerError {
-				mockDB.On("UpdateFlat", tc.inputFlat).Return(models.Flat{}, errors.New("database error")).Once()
+				mockDB.On("UpdateFlat", mock.Anything, expectedInput).Return(models.Flat{}, errors.New("database error")).Once()
+			} else if tc.dbError != nil {
+				mockDB.On("UpdateFlat", mock.Anything, expectedInput).Return(models.Flat{}, tc.dbError).Once()
 			}
 
 			var token string
 			if tc.authorized {
-				token, _ = PerformLogin("moderator")
+				token, _ = performLogin("moderator")
 			}
 
 			var body []byte
-			if tc.expectedCode == http.StatusBadRequest {
+			if tc.name == "JSON Unmarshal error" {
 				body = []byte(`{"invalidJson"}`) // Неверный JSON
 			} else {
 				body, _ = json.Marshal(tc.inputFlat)
@@ -417,7 +721,7 @@ func TestFlatUpdateHandler(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
-			handler := New(mockDB, mockCache)
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
 			handler.ServeHTTP(rr, req)
 
 			if !tc.authorized && rr.Code == http.StatusUnauthorized {
@@ -439,3 +743,1556 @@ func TestFlatUpdateHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestFlatModerationLeaseHandlers(t *testing.T) {
+	expiresAt := time.Date(2024, 8, 9, 12, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		path         string
+		status       string
+		returnedFlat models.Flat
+		dbError      error
+		expectedCode int
+	}{
+		// Тест 1: Модератор возвращает квартиру в очередь
+		{
+			name:         "Release flat",
+			path:         "/flat/12/release",
+			status:       "created",
+			returnedFlat: models.Flat{Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "created"},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Модератор продлевает блокировку
+		{
+			name:         "Extend lease",
+			path:         "/flat/12/extend",
+			status:       "on moderation",
+			returnedFlat: models.Flat{Id: 12, HouseId: 100, Price: 199000, Rooms: 3, Num: 10, Status: "on moderation", ModeratorId: models.DummyModeratorId, ModerationExpiresAt: &expiresAt},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 3: Квартиру держит другой модератор
+		{
+			name:         "Release flat locked by another moderator",
+			path:         "/flat/12/release",
+			status:       "created",
+			dbError:      storage.ErrFlatLocked,
+			expectedCode: http.StatusConflict,
+		},
+		// Тест 4: Квартира не на модерации
+		{
+			name:         "Extend flat that is not on moderation",
+			path:         "/flat/12/extend",
+			status:       "on moderation",
+			dbError:      storage.ErrInvalidStatusTransition,
+			expectedCode: http.StatusConflict,
+		},
+		// Тест 5: Неверный идентификатор квартиры
+		{
+			name:         "Invalid flat id",
+			path:         "/flat/abc/release",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			if tc.status != "" {
+				input := models.Flat{Id: 12, Status: tc.status, ModeratorId: models.DummyModeratorId}
+				mockDB.On("UpdateFlat", mock.Anything, input).Return(tc.returnedFlat, tc.dbError).Once()
+			}
+
+			if tc.expectedCode == http.StatusOK {
+				mockCache.On("DeleteFlatsByHouseId", mock.Anything, int64(100), "moderator").Once()
+			}
+
+			token, _ := performLogin("moderator")
+
+			req, err := http.NewRequest("POST", tc.path, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var flat models.Flat
+				err = json.Unmarshal(rr.Body.Bytes(), &flat)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.returnedFlat, flat)
+			}
+
+			mockDB.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerationQueueHandler(t *testing.T) {
+	priceMin := int64(100000)
+	cursor := models.FlatCursor{CreatedAt: time.Date(2024, 8, 9, 12, 30, 0, 0, time.UTC), Id: 7}
+
+	testCases := []struct {
+		name           string
+		userType       string
+		query          string
+		expectedFilter *models.ModerationQueueFilter
+		page           models.ModerationQueuePage
+		expectedCode   int
+	}{
+		// Тест 1: Первая страница очереди без фильтров
+		{
+			name:           "First page",
+			userType:       "moderator",
+			expectedFilter: &models.ModerationQueueFilter{Limit: 20},
+			page: models.ModerationQueuePage{
+				Flats:      []models.Flat{{Id: 7, HouseId: 1, Price: 100000, Rooms: 2, Num: 1, Status: "created"}},
+				NextCursor: cursor.Encode(),
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Фильтры и курсор передаются в базу данных
+		{
+			name:           "Filters and cursor",
+			userType:       "moderator",
+			query:          "?house_id=1&developer=Мэрия&price_min=100000&limit=5&cursor=" + cursor.Encode(),
+			expectedFilter: &models.ModerationQueueFilter{HouseId: 1, Developer: "Мэрия", PriceMin: &priceMin, After: &cursor, Limit: 5},
+			page:           models.ModerationQueuePage{Flats: []models.Flat{}},
+			expectedCode:   http.StatusOK,
+		},
+		// Тест 3: Минимальная цена больше максимальной
+		{
+			name:         "Invalid price range",
+			userType:     "moderator",
+			query:        "?price_min=200&price_max=100",
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 4: Неверный курсор
+		{
+			name:         "Invalid cursor",
+			userType:     "moderator",
+			query:        "?cursor=broken",
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 5: Очередь недоступна клиентам
+		{
+			name:         "Client access",
+			userType:     "client",
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			if tc.expectedFilter != nil {
+				mockDB.On("GetModerationQueue", mock.Anything, *tc.expectedFilter).Return(tc.page, nil).Once()
+			}
+
+			token, _ := performLogin(tc.userType)
+
+			req, err := http.NewRequest("GET", "/moderation/queue"+tc.query, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var page models.ModerationQueuePage
+				err = json.Unmarshal(rr.Body.Bytes(), &page)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.page, page)
+			}
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerationQueueNextHandler(t *testing.T) {
+	mockDB := new(mocks.Database)
+	mockCache := new(mocks.Cache)
+	mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+	handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+
+	token, err := performLogin("moderator")
+	assert.NoError(t, err)
+
+	filter := models.ModerationQueueFilter{HouseId: 1, Limit: 20}
+	taken := models.Flat{Id: 7, HouseId: 1, Price: 100000, Rooms: 2, Num: 1, Status: "on moderation", ModeratorId: models.DummyModeratorId}
+
+	// Тест 1: Модератору назначается следующая квартира
+	mockDB.On("TakeNextFlatForModeration", mock.Anything, models.DummyModeratorId, filter).Return(taken, nil).Once()
+	mockCache.On("DeleteFlatsByHouseId", mock.Anything, int64(1), "moderator").Once()
+
+	req, err := http.NewRequest("POST", "/moderation/queue/next?house_id=1", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", token)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var flat models.Flat
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &flat))
+	assert.Equal(t, taken, flat)
+
+	// Тест 2: Очередь пуста
+	mockDB.On("TakeNextFlatForModeration", mock.Anything, models.DummyModeratorId, filter).Return(models.Flat{}, storage.ErrNotFound).Once()
+
+	req, err = http.NewRequest("POST", "/moderation/queue/next?house_id=1", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", token)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	mockDB.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestFlatHistoryHandler(t *testing.T) {
+	createdAt := time.Date(2024, 8, 9, 12, 0, 0, 0, time.UTC)
+	history := []models.FlatStatusChange{
+		{Id: 1, FlatId: 12, ActorId: models.DummyClientId, NewStatus: "created", CreatedAt: createdAt},
+		{Id: 2, FlatId: 12, ActorId: models.DummyModeratorId, OldStatus: "created", NewStatus: "on moderation", CreatedAt: createdAt.Add(time.Hour)},
+		{Id: 3, FlatId: 12, ActorId: models.DummyModeratorId, OldStatus: "on moderation", NewStatus: "declined", Reason: "Нет фотографий", CreatedAt: createdAt.Add(2 * time.Hour)},
+	}
+	foreignHistory := []models.FlatStatusChange{
+		{Id: 4, FlatId: 13, ActorId: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c15", NewStatus: "created", CreatedAt: createdAt},
+	}
+
+	testCases := []struct {
+		name         string
+		userType     string
+		flatId       int64
+		owner        string
+		history      []models.FlatStatusChange
+		dbError      error
+		expectedCode int
+	}{
+		// Тест 1: Модератор видит историю любой квартиры
+		{name: "Moderator", userType: "moderator", flatId: 13, history: foreignHistory, expectedCode: http.StatusOK},
+		// Тест 2: Владелец видит историю своей квартиры
+		{name: "Owner", userType: "client", flatId: 12, owner: models.DummyClientId, history: history, expectedCode: http.StatusOK},
+		// Тест 3: Другой клиент не видит историю
+		{name: "Not an owner", userType: "client", flatId: 13, owner: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c15", expectedCode: http.StatusForbidden},
+		// Тест 4: Квартира не найдена
+		{name: "Flat not found", userType: "moderator", flatId: 9999, dbError: storage.ErrNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			if tc.owner != "" {
+				mockDB.On("GetFlatById", mock.Anything, tc.flatId).Return(models.Flat{Id: tc.flatId, OwnerId: tc.owner}, nil).Once()
+			}
+
+			if tc.expectedCode != http.StatusForbidden {
+				mockDB.On("GetFlatStatusHistory", mock.Anything, tc.flatId).Return(tc.history, tc.dbError).Once()
+			}
+
+			token, _ := performLogin(tc.userType)
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("/flat/%d/history", tc.flatId), nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var response map[string][]models.FlatStatusChange
+				err = json.Unmarshal(rr.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.history, response["history"])
+			}
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlatResubmitHandler(t *testing.T) {
+	price := int64(90000)
+
+	testCases := []struct {
+		name         string
+		body         string
+		expectedEdit *models.FlatEdit
+		returnedFlat models.Flat
+		dbError      error
+		expectedCode int
+	}{
+		// Тест 1: Владелец меняет цену и отправляет квартиру на модерацию
+		{
+			name:         "Resubmit with new price",
+			body:         `{"price": 90000}`,
+			expectedEdit: &models.FlatEdit{Price: &price},
+			returnedFlat: models.Flat{Id: 12, HouseId: 100, Price: 90000, Rooms: 3, Num: 10, Status: "created"},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Повторная отправка без изменений
+		{
+			name:         "Resubmit without changes",
+			expectedEdit: &models.FlatEdit{},
+			returnedFlat: models.Flat{Id: 12, HouseId: 100, Price: 100000, Rooms: 3, Num: 10, Status: "created"},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 3: Неверное количество комнат
+		{
+			name:         "Invalid rooms",
+			body:         `{"rooms": 0}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 4: Квартира принадлежит другому пользователю
+		{
+			name:         "Not an owner",
+			expectedEdit: &models.FlatEdit{},
+			dbError:      storage.ErrNotFlatOwner,
+			expectedCode: http.StatusForbidden,
+		},
+		// Тест 5: Квартира не отклонена
+		{
+			name:         "Flat is not declined",
+			expectedEdit: &models.FlatEdit{},
+			dbError:      storage.ErrInvalidStatusTransition,
+			expectedCode: http.StatusConflict,
+		},
+		// Тест 6: Номер квартиры при повторной отправке не меняется
+		{
+			name:         "Flat number is not accepted",
+			body:         `{"price": 90000, "flat_num": 11}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			if tc.expectedEdit != nil {
+				mockDB.On("ResubmitFlat", mock.Anything, int64(12), models.DummyClientId, *tc.expectedEdit).Return(tc.returnedFlat, tc.dbError).Once()
+			}
+
+			if tc.expectedCode == http.StatusOK {
+				mockCache.On("DeleteFlatsByHouseId", mock.Anything, int64(100), "moderator").Once()
+			}
+
+			token, _ := performLogin("client")
+
+			req, err := http.NewRequest("POST", "/flat/12/resubmit", bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var flat models.Flat
+				err = json.Unmarshal(rr.Body.Bytes(), &flat)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.returnedFlat, flat)
+			}
+
+			mockDB.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlatEditHandler(t *testing.T) {
+	rooms := 2
+	flatNum := 11
+	price := int64(120000)
+
+	testCases := []struct {
+		name         string
+		body         string
+		expectedEdit *models.FlatEdit
+		returnedFlat models.Flat
+		dbError      error
+		expectedCode int
+	}{
+		// Тест 1: Владелец меняет количество комнат
+		{
+			name:         "Owner changes rooms",
+			body:         `{"rooms": 2}`,
+			expectedEdit: &models.FlatEdit{Rooms: &rooms},
+			returnedFlat: models.Flat{Id: 12, HouseId: 100, Price: 100000, Rooms: 2, Num: 10, Status: "created", OwnerId: models.DummyClientId},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Отрицательная цена
+		{
+			name:         "Negative price",
+			body:         `{"price": -1}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 3: Квартира принадлежит другому пользователю
+		{
+			name:         "Not an owner",
+			body:         `{"rooms": 2}`,
+			expectedEdit: &models.FlatEdit{Rooms: &rooms},
+			dbError:      storage.ErrNotFlatOwner,
+			expectedCode: http.StatusForbidden,
+		},
+		// Тест 4: Номер квартиры уже занят в доме
+		{
+			name:         "Flat number taken",
+			body:         `{"flat_num": 11}`,
+			expectedEdit: &models.FlatEdit{Num: &flatNum},
+			dbError:      storage.ErrFlatNumberTaken,
+			expectedCode: http.StatusConflict,
+		},
+		// Тест 5: Одобренная квартира после изменения возвращается на модерацию
+		{
+			name:         "Approved flat goes back to moderation",
+			body:         `{"price": 120000, "flat_num": 11}`,
+			expectedEdit: &models.FlatEdit{Price: &price, Num: &flatNum},
+			returnedFlat: models.Flat{Id: 12, HouseId: 100, Price: 120000, Rooms: 3, Num: 11, Status: "created", OwnerId: models.DummyClientId},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 6: Пустое изменение
+		{
+			name:         "Nothing to change",
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 7: Неверный номер квартиры
+		{
+			name:         "Invalid flat number",
+			body:         `{"flat_num": 0}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 8: Квартира на модерации у модератора
+		{
+			name:         "Flat under moderation",
+			body:         `{"price": 120000}`,
+			expectedEdit: &models.FlatEdit{Price: &price},
+			dbError:      storage.ErrFlatLocked,
+			expectedCode: http.StatusConflict,
+		},
+		// Тест 9: Неизвестное поле не отбрасывается молча
+		{
+			name:         "Unknown field",
+			body:         `{"price": 120000, "flat_number": 5}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			if tc.expectedEdit != nil {
+				mockDB.On("EditFlat", mock.Anything, int64(12), models.DummyClientId, *tc.expectedEdit).Return(tc.returnedFlat, tc.dbError).Once()
+			}
+
+			if tc.expectedCode == http.StatusOK {
+				mockCache.On("DeleteFlatsByHouseId", mock.Anything, int64(100), "moderator").Once()
+				mockCache.On("DeleteFlatsByHouseId", mock.Anything, int64(100), "client").Once()
+			}
+
+			token, _ := performLogin("client")
+
+			req, err := http.NewRequest("PATCH", "/flat/12", bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var flat models.Flat
+				err = json.Unmarshal(rr.Body.Bytes(), &flat)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.returnedFlat, flat)
+			}
+
+			mockDB.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlatArchiveHandlers(t *testing.T) {
+	deletedAt := time.Date(2024, 8, 9, 12, 0, 0, 0, time.UTC)
+	archived := models.Flat{Id: 12, HouseId: 100, Price: 100000, Rooms: 3, Num: 10, Status: "approved", OwnerId: models.DummyClientId, DeletedAt: &deletedAt}
+	restored := models.Flat{Id: 12, HouseId: 100, Price: 100000, Rooms: 3, Num: 10, Status: "approved", OwnerId: models.DummyClientId}
+
+	testCases := []struct {
+		name         string
+		userType     string
+		method       string
+		path         string
+		setup        func(mockDB *mocks.Database)
+		expectedCode int
+	}{
+		// Тест 1: Владелец архивирует свою квартиру
+		{
+			name:     "Owner deletes flat",
+			userType: "client",
+			method:   "DELETE",
+			path:     "/flat/12",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("GetFlatById", mock.Anything, int64(12)).Return(restored, nil).Once()
+				mockDB.On("DeleteFlat", mock.Anything, int64(12)).Return(archived, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Чужую квартиру архивировать нельзя
+		{
+			name:     "Client deletes foreign flat",
+			userType: "client",
+			method:   "DELETE",
+			path:     "/flat/12",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("GetFlatById", mock.Anything, int64(12)).Return(models.Flat{Id: 12, OwnerId: "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c15"}, nil).Once()
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		// Тест 3: Модератор архивирует любую квартиру
+		{
+			name:     "Moderator deletes flat",
+			userType: "moderator",
+			method:   "DELETE",
+			path:     "/flat/12",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("DeleteFlat", mock.Anything, int64(12)).Return(archived, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 4: Квартира дома в архиве восстанавливается только вместе с домом
+		{
+			name:     "Restore flat of archived house",
+			userType: "moderator",
+			method:   "POST",
+			path:     "/flat/12/restore",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("RestoreFlat", mock.Anything, int64(12)).Return(models.Flat{}, storage.ErrHouseDeleted).Once()
+			},
+			expectedCode: http.StatusConflict,
+		},
+		// Тест 5: Модератор восстанавливает квартиру
+		{
+			name:     "Restore flat",
+			userType: "moderator",
+			method:   "POST",
+			path:     "/flat/12/restore",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("RestoreFlat", mock.Anything, int64(12)).Return(restored, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 6: Клиент не может восстановить квартиру
+		{
+			name:         "Client restores flat",
+			userType:     "client",
+			method:       "POST",
+			path:         "/flat/12/restore",
+			setup:        func(mockDB *mocks.Database) {},
+			expectedCode: http.StatusForbidden,
+		},
+		// Тест 7: Модератор архивирует дом вместе с квартирами
+		{
+			name:     "Delete house",
+			userType: "moderator",
+			method:   "DELETE",
+			path:     "/house/100",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("DeleteHouse", mock.Anything, int64(100)).Return(models.House{Id: 100, Address: "Лесная улица, 7", Year: 2000, DeletedAt: &deletedAt}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 8: Дом не найден
+		{
+			name:     "Restore unknown house",
+			userType: "moderator",
+			method:   "POST",
+			path:     "/house/100/restore",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("RestoreHouse", mock.Anything, int64(100)).Return(models.House{}, storage.ErrNotFound).Once()
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+			tc.setup(mockDB)
+
+			if tc.expectedCode == http.StatusOK {
+				mockCache.On("DeleteFlatsByHouseId", mock.Anything, int64(100), "moderator").Once()
+				mockCache.On("DeleteFlatsByHouseId", mock.Anything, int64(100), "client").Once()
+			}
+
+			token, _ := performLogin(tc.userType)
+
+			req, err := http.NewRequest(tc.method, tc.path, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			mockDB.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHouseHandlers(t *testing.T) {
+	deletedAt := time.Date(2024, 8, 9, 12, 0, 0, 0, time.UTC)
+	house := models.House{Id: 100, Address: "Лесная улица, 7", Year: 2000, Developer: "Мэрия города", CreatedAt: "2024-08-09T12:00:00.000Z"}
+	archived := house
+	archived.DeletedAt = &deletedAt
+
+	address := "Лесная улица, 9"
+	year := 2001
+	yearMin := 2000
+	updatedSince := time.Date(2024, 8, 9, 12, 0, 0, 0, time.UTC)
+	cursor := models.HouseCursor{Id: 100}
+
+	testCases := []struct {
+		name         string
+		userType     string
+		method       string
+		path         string
+		body         string
+		setup        func(mockDB *mocks.Database)
+		expectedCode int
+	}{
+		// Тест 1: Клиент получает информацию о доме
+		{
+			name:     "House info",
+			userType: "client",
+			method:   "GET",
+			path:     "/house/100/info",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("GetHouseById", mock.Anything, int64(100)).Return(house, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Дом в архиве не виден клиенту
+		{
+			name:     "Archived house info for client",
+			userType: "client",
+			method:   "GET",
+			path:     "/house/100/info",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("GetHouseById", mock.Anything, int64(100)).Return(archived, nil).Once()
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		// Тест 3: Модератор видит дом в архиве
+		{
+			name:     "Archived house info for moderator",
+			userType: "moderator",
+			method:   "GET",
+			path:     "/house/100/info",
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("GetHouseById", mock.Anything, int64(100)).Return(archived, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 4: Модератор исправляет адрес и год, адрес очищается от пробелов
+		{
+			name:     "Edit house",
+			userType: "moderator",
+			method:   "PATCH",
+			path:     "/house/100",
+			body:     `{"address": " Лесная улица, 9 ", "year": 2001}`,
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("UpdateHouse", mock.Anything, int64(100), models.HouseEdit{Address: &address, Year: &year}).Return(house, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 5: Пустой адрес
+		{
+			name:         "Empty address",
+			userType:     "moderator",
+			method:       "PATCH",
+			path:         "/house/100",
+			body:         `{"address": "  "}`,
+			setup:        func(mockDB *mocks.Database) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 6: Нечего изменять
+		{
+			name:         "Empty edit",
+			userType:     "moderator",
+			method:       "PATCH",
+			path:         "/house/100",
+			body:         `{}`,
+			setup:        func(mockDB *mocks.Database) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 7: Клиент не может изменять дом
+		{
+			name:         "Client edits house",
+			userType:     "client",
+			method:       "PATCH",
+			path:         "/house/100",
+			body:         `{"year": 2001}`,
+			setup:        func(mockDB *mocks.Database) {},
+			expectedCode: http.StatusForbidden,
+		},
+		// Тест 8: Дом не найден или в архиве
+		{
+			name:     "Edit unknown house",
+			userType: "moderator",
+			method:   "PATCH",
+			path:     "/house/100",
+			body:     `{"year": 2001}`,
+			setup: func(mockDB *mocks.Database) {
+				mockDB.On("UpdateHouse", mock.Anything, int64(100), models.HouseEdit{Year: &year}).Return(models.House{}, storage.ErrNotFound).Once()
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		// Тест 9: Фильтры и курсор списка домов передаются в базу данных
+		{
+			name:     "List houses",
+			userType: "client",
+			method:   "GET",
+			path:     "/houses?developer=Мэрия&year_min=2000&updated_since=2024-08-09T12:00:00Z&limit=5&cursor=" + cursor.Encode(),
+			setup: func(mockDB *mocks.Database) {
+				filter := models.HouseFilter{Developer: "Мэрия", YearMin: &yearMin, UpdatedSince: &updatedSince, After: &cursor, Limit: 5}
+				mockDB.On("GetHouses", mock.Anything, filter).Return(models.HousePage{Houses: []models.House{house}}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 10: Минимальный год больше максимального
+		{
+			name:         "Invalid year range",
+			userType:     "client",
+			method:       "GET",
+			path:         "/houses?year_min=2010&year_max=2000",
+			setup:        func(mockDB *mocks.Database) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 11: Неверная дата
+		{
+			name:         "Invalid updated_since",
+			userType:     "client",
+			method:       "GET",
+			path:         "/houses?updated_since=yesterday",
+			setup:        func(mockDB *mocks.Database) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+			tc.setup(mockDB)
+
+			token, _ := performLogin(tc.userType)
+
+			req, err := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlatSearchHandler(t *testing.T) {
+	priceMax := int64(150000)
+	rooms := 3
+	yearMin := 2010
+	cursor := models.FlatSearchCursor{Sort: models.FlatSortPriceAsc, Price: 120000, Id: 7}
+
+	testCases := []struct {
+		name           string
+		userType       string
+		query          string
+		expectedFilter *models.FlatSearchFilter
+		expectedCode   int
+	}{
+		// Тест 1: Поиск без фильтров, сначала новые квартиры
+		{
+			name:           "Default search",
+			userType:       "client",
+			expectedFilter: &models.FlatSearchFilter{Sort: models.FlatSortNewest, Limit: 20},
+			expectedCode:   http.StatusOK,
+		},
+		// Тест 2: Фильтры, сортировка и курсор передаются в базу данных
+		{
+			name:     "Filters and cursor",
+			userType: "moderator",
+			query:    "?price_max=150000&rooms_min=3&rooms_max=3&year_min=2010&address=%20Springfield%20&sort=price_asc&limit=5&cursor=" + cursor.Encode(),
+			expectedFilter: &models.FlatSearchFilter{
+				PriceMax: &priceMax,
+				RoomsMin: &rooms,
+				RoomsMax: &rooms,
+				YearMin:  &yearMin,
+				Address:  "Springfield",
+				Sort:     models.FlatSortPriceAsc,
+				After:    &cursor,
+				Limit:    5,
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 3: Курсор выдан для другой сортировки
+		{
+			name:         "Cursor of another sort",
+			userType:     "client",
+			query:        "?sort=price_desc&cursor=" + cursor.Encode(),
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 4: Неизвестная сортировка
+		{
+			name:         "Invalid sort",
+			userType:     "client",
+			query:        "?sort=rooms",
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 5: Отрицательное количество комнат
+		{
+			name:         "Invalid rooms",
+			userType:     "client",
+			query:        "?rooms_min=-1",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			page := models.FlatSearchPage{Flats: []models.Flat{{Id: 7, HouseId: 1, Price: 120000, Rooms: 3, Num: 1, Status: "approved"}}}
+
+			if tc.expectedFilter != nil {
+				mockDB.On("SearchFlats", mock.Anything, *tc.expectedFilter, tc.userType).Return(page, nil).Once()
+			}
+
+			token, _ := performLogin(tc.userType)
+
+			req, err := http.NewRequest("GET", "/flats/search"+tc.query, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var response models.FlatSearchPage
+				err = json.Unmarshal(rr.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, page, response)
+			}
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMyFlatsHandler(t *testing.T) {
+	mockDB := new(mocks.Database)
+	mockCache := new(mocks.Cache)
+	mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+	flats := []models.Flat{
+		{Id: 1, HouseId: 1, Price: 100000, Rooms: 3, Num: 101, Status: "created", OwnerId: models.DummyClientId},
+		{Id: 2, HouseId: 2, Price: 150000, Rooms: 4, Num: 201, Status: "declined", OwnerId: models.DummyClientId,
+			DeclineReasonCode: "wrong_price", DeclineReason: "Цена завышена"},
+	}
+
+	// Тест 1: Пользователь получает свои квартиры во всех статусах
+	mockDB.On("GetFlatsByOwner", mock.Anything, models.DummyClientId).Return(flats, nil).Once()
+
+	token, err := performLogin("client")
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("GET", "/me/flats", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", token)
+
+	rr := httptest.NewRecorder()
+	New(mockDB, mockCache, testKeys, testConfig.Auth).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response map[string][]models.Flat
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, flats, response["flats"])
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeclineReasonHandlers(t *testing.T) {
+	mockDB := new(mocks.Database)
+	mockCache := new(mocks.Cache)
+	mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+	handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+
+	token, err := performLogin("moderator")
+	assert.NoError(t, err)
+
+	send := func(method string, path string, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, path, bytes.NewBufferString(body))
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", token)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	reasons := []models.DeclineReason{{Code: "other", Description: "Другая причина"}}
+
+	// Тест 1: Модератор получает список причин
+	mockDB.On("GetDeclineReasons", mock.Anything).Return(reasons, nil).Once()
+
+	rr := send("GET", "/decline-reasons", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response map[string][]models.DeclineReason
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, reasons, response["reasons"])
+
+	// Тест 2: Модератор добавляет причину
+	newReason := models.DeclineReason{Code: "no_photos", Description: "Нет фотографий"}
+	mockDB.On("SaveDeclineReason", mock.Anything, newReason).Return(newReason, nil).Once()
+
+	rr = send("POST", "/decline-reasons", `{"code": "no_photos", "description": "Нет фотографий"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Тест 3: Код причины в неверном формате
+	rr = send("POST", "/decline-reasons", `{"code": "No Photos", "description": "Нет фотографий"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Тест 4: Удаление неизвестной причины
+	mockDB.On("DeactivateDeclineReason", mock.Anything, "unknown").Return(storage.ErrNotFound).Once()
+
+	rr = send("DELETE", "/decline-reasons/unknown", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestSubscribeHandler(t *testing.T) {
+
+	testCases := []struct {
+		name         string
+		houseId      string
+		body         []byte
+		authorized   bool
+		expectedCode int
+		dbError      error
+	}{
+		// Тест 1: Успешная подписка на дом
+		{
+			name:         "Successful subscription",
+			houseId:      "1",
+			body:         []byte(`{"email": "test@gmail.com"}`),
+			authorized:   true,
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Некорректный email
+		{
+			name:         "Invalid email",
+			houseId:      "1",
+			body:         []byte(`{"email": "not an email"}`),
+			authorized:   true,
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 3: Некорректный идентификатор дома
+		{
+			name:         "Invalid house id",
+			houseId:      "abc",
+			body:         []byte(`{"email": "test@gmail.com"}`),
+			authorized:   true,
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 4: Неавторизованный запрос
+		{
+			name:         "Unauthorized access",
+			houseId:      "1",
+			body:         []byte(`{"email": "test@gmail.com"}`),
+			authorized:   false,
+			expectedCode: http.StatusUnauthorized,
+		},
+		// Тест 5: Ошибка базы данных
+		{
+			name:         "Database error",
+			houseId:      "1",
+			body:         []byte(`{"email": "test@gmail.com"}`),
+			authorized:   true,
+			expectedCode: http.StatusInternalServerError,
+			dbError:      errors.New("database error"),
+		},
+		// Тест 6: Дом не найден или в архиве
+		{
+			name:         "House not found",
+			houseId:      "1",
+			body:         []byte(`{"email": "test@gmail.com"}`),
+			authorized:   true,
+			expectedCode: http.StatusNotFound,
+			dbError:      storage.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			subscription := models.Subscription{HouseId: 1, Email: "test@gmail.com"}
+			if tc.expectedCode == http.StatusOK || tc.dbError != nil {
+				mockDB.On("CreateSubscription", mock.Anything, subscription).Return(subscription, tc.dbError).Once()
+			}
+
+			var token string
+			if tc.authorized {
+				token, _ = performLogin("client")
+			}
+
+			req, err := http.NewRequest("POST", fmt.Sprintf("/house/%s/subscribe", tc.houseId), bytes.NewBuffer(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRefreshTokenHandler(t *testing.T) {
+
+	testCases := []struct {
+		name         string
+		body         []byte
+		rotateError  error
+		expectedCode int
+	}{
+		// Тест 1: Успешное обновление токенов
+		{
+			name:         "Successful refresh",
+			body:         []byte(`{"refresh_token": "old-token"}`),
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Пустой refresh токен
+		{
+			name:         "Empty refresh token",
+			body:         []byte(`{}`),
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 3: Неизвестный или истекший refresh токен
+		{
+			name:         "Unknown refresh token",
+			body:         []byte(`{"refresh_token": "old-token"}`),
+			rotateError:  storage.ErrNotFound,
+			expectedCode: http.StatusUnauthorized,
+		},
+		// Тест 4: Повторное использование refresh токена
+		{
+			name:         "Reused refresh token",
+			body:         []byte(`{"refresh_token": "old-token"}`),
+			rotateError:  storage.ErrRefreshTokenReused,
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+
+			user := models.User{Id: "cae36e0f-69e5-4fa8-a179-a52d083c5549", UserType: "client"}
+
+			if tc.expectedCode != http.StatusBadRequest {
+				mockDB.On("RotateRefreshToken", mock.Anything, auth.HashRefreshToken("old-token"), mock.Anything).
+					Return(models.RefreshToken{UserId: user.Id}, tc.rotateError).Once()
+			}
+
+			if tc.expectedCode == http.StatusOK {
+				mockDB.On("GetUserById", mock.Anything, user.Id).Return(user, nil).Once()
+			}
+
+			req, err := http.NewRequest("POST", "/token/refresh", bytes.NewBuffer(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var tokens models.AuthorizationToken
+				err = json.Unmarshal(rr.Body.Bytes(), &tokens)
+				assert.NoError(t, err)
+				assert.NotEmpty(t, tokens.Token)
+				assert.NotEmpty(t, tokens.RefreshToken)
+				assert.NotEqual(t, "old-token", tokens.RefreshToken)
+			}
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogoutHandler(t *testing.T) {
+	mockDB := new(mocks.Database)
+	mockCache := new(mocks.Cache)
+	handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+
+	token, err := performLogin("client")
+	assert.NoError(t, err)
+
+	// Тест 1: Выход отзывает токен доступа и refresh токен
+	mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Once()
+	mockDB.On("RevokeRefreshToken", mock.Anything, auth.HashRefreshToken("refresh"), models.DummyClientId).Return(nil).Once()
+	mockCache.On("RevokeToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+	req, err := http.NewRequest("POST", "/logout", bytes.NewBufferString(`{"refresh_token": "refresh"}`))
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", token)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Тест 2: Отозванный токен больше не принимается
+	mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	req, err = http.NewRequest("GET", "/house/1", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", token)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockDB.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestRegisterHandler(t *testing.T) {
+	testCases := []struct {
+		name         string
+		dbError      error
+		expectedCode int
+	}{
+		// Тест 1: Успешная регистрация
+		{
+			name:         "Registered",
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Email уже зарегистрирован
+		{
+			name:         "Email taken",
+			dbError:      storage.ErrEmailTaken,
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+
+			user := models.User{Id: "cae36e0f-69e5-4fa8-a179-a52d083c5549"}
+			mockDB.On("CreateUser", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+				return u.Email == "test@gmail.com" && u.Password != "secret"
+			})).Return(user, tc.dbError).Once()
+
+			req, err := http.NewRequest("POST", "/register", bytes.NewBufferString(`{"email": "test@gmail.com", "password": "secret", "user_type": "client"}`))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusConflict {
+				var response apierror.Response
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, apierror.CodeEmailTaken, response.Code)
+			}
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStoredUserType(t *testing.T) {
+	mockDB := new(mocks.Database)
+	mockCache := new(mocks.Cache)
+	mockCache.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+	handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+
+	userId := "cae36e0f-69e5-4fa8-a179-a52d083c5549"
+	token, err := testKeys.Sign(&models.CustomClaims{
+		UserId: userId,
+		Type:   "moderator",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	assert.NoError(t, err)
+
+	// Тест 1: Понижение до клиента действует на уже выданный токен модератора
+	mockDB.On("GetUserById", mock.Anything, userId).Return(models.User{Id: userId, UserType: "client"}, nil).Once()
+
+	req, err := http.NewRequest("GET", "/moderation/queue", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestLoginHandler(t *testing.T) {
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	user := models.User{Id: "cae36e0f-69e5-4fa8-a179-a52d083c5549", Email: "test@gmail.com", Password: string(passwordHash), UserType: "client"}
+
+	testCases := []struct {
+		name         string
+		body         string
+		setupMock    func(mockDB *mocks.Database)
+		expectedCode int
+	}{
+		// Тест 1: Успешный вход по email
+		{
+			name: "Login by email",
+			body: `{"email": "test@gmail.com", "password": "secret"}`,
+			setupMock: func(mockDB *mocks.Database) {
+				mockDB.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil).Once()
+				mockDB.On("CreateRefreshToken", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: Успешный вход по идентификатору
+		{
+			name: "Login by id",
+			body: `{"id": "cae36e0f-69e5-4fa8-a179-a52d083c5549", "password": "secret"}`,
+			setupMock: func(mockDB *mocks.Database) {
+				mockDB.On("GetUserById", mock.Anything, user.Id).Return(user, nil).Once()
+				mockDB.On("CreateRefreshToken", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		// Тест 3: Неверный пароль
+		{
+			name: "Wrong password",
+			body: `{"email": "test@gmail.com", "password": "wrong"}`,
+			setupMock: func(mockDB *mocks.Database) {
+				mockDB.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil).Once()
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 4: Неизвестный пользователь, ответ такой же как при неверном пароле
+		{
+			name: "Unknown user",
+			body: `{"email": "unknown@gmail.com", "password": "secret"}`,
+			setupMock: func(mockDB *mocks.Database) {
+				mockDB.On("GetUserByEmail", mock.Anything, "unknown@gmail.com").Return(models.User{}, storage.ErrNotFound).Once()
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		// Тест 5: Неизвестный идентификатор, ответ такой же как при неверном пароле
+		{
+			name: "Unknown id",
+			body: `{"id": "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c15", "password": "secret"}`,
+			setupMock: func(mockDB *mocks.Database) {
+				mockDB.On("GetUserById", mock.Anything, "5d1ef6a4-9a43-4a0e-9c4e-0d4a1f2b3c15").Return(models.User{}, storage.ErrNotFound).Once()
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	var failedBodies []string
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+			tc.setupMock(mockDB)
+
+			req, err := http.NewRequest("POST", "/login", bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Request-Id", "login-test")
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var tokens models.AuthorizationToken
+				err = json.Unmarshal(rr.Body.Bytes(), &tokens)
+				assert.NoError(t, err)
+				assert.NotEmpty(t, tokens.Token)
+				assert.NotEmpty(t, tokens.RefreshToken)
+			} else {
+				failedBodies = append(failedBodies, rr.Body.String())
+			}
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+
+	for _, body := range failedBodies {
+		assert.Equal(t, failedBodies[0], body, "Login failures must not reveal whether the user exists")
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedFields []string
+	}{
+		// Тест 1: Отрицательная цена и ноль комнат отклоняются до обращения к базе данных
+		{
+			name:           "Invalid flat",
+			method:         "POST",
+			path:           "/flat/create",
+			body:           `{"house_id": 1, "price": -1, "rooms": 0, "flat_num": 1}`,
+			expectedFields: []string{"price", "rooms"},
+		},
+		// Тест 2: Обязательное поле отсутствует
+		{
+			name:           "Missing year",
+			method:         "POST",
+			path:           "/house/create",
+			body:           `{"address": "Лесная улица, 7"}`,
+			expectedFields: []string{"year"},
+		},
+		// Тест 3: Неизвестный тип пользователя и неверный email при регистрации
+		{
+			name:           "Invalid registration",
+			method:         "POST",
+			path:           "/register",
+			body:           `{"email": "not an email", "password": "secret", "user_type": "admin"}`,
+			expectedFields: []string{"email", "user_type"},
+		},
+		// Тест 4: Идентификатор в пути не является числом
+		{
+			name:           "Invalid path parameter",
+			method:         "GET",
+			path:           "/house/abc/info",
+			expectedFields: []string{"id"},
+		},
+		// Тест 5: Параметр запроса вне допустимого диапазона
+		{
+			name:           "Invalid query parameter",
+			method:         "GET",
+			path:           "/flats/search?limit=1000",
+			expectedFields: []string{"limit"},
+		},
+		// Тест 6: Тело запроса обязательно
+		{
+			name:           "Missing body",
+			method:         "POST",
+			path:           "/flat/update",
+			expectedFields: []string{"body"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockDB := new(mocks.Database)
+			mockCache := new(mocks.Cache)
+
+			token, _ := performLogin("moderator")
+
+			req, err := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+
+			rr := httptest.NewRecorder()
+			handler := New(mockDB, mockCache, testKeys, testConfig.Auth)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+			var response apierror.Response
+			err = json.Unmarshal(rr.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, apierror.CodeInvalidRequest, response.Code)
+
+			var fields []string
+			for _, field := range response.Fields {
+				fields = append(fields, field.Field)
+				assert.NotEmpty(t, field.Message)
+			}
+
+			assert.ElementsMatch(t, tc.expectedFields, fields)
+
+			mockDB.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProbeHandlers(t *testing.T) {
+	testCases := []struct {
+		name         string
+		target       string
+		dbErr        error
+		cacheErr     error
+		expectedCode int
+	}{
+		// Тест 1: Проверка живости не обращается к базе данных и кэшу
+		{
+			name:         "Liveness",
+			target:       "/healthz",
+			expectedCode: http.StatusOK,
+		},
+		// Тест 2: База данных и кэш доступны
+		{
+			name:         "Ready",
+			target:       "/readyz",
+			expectedCode: http.StatusOK,
+		},
+		// Тест 3: База данных недоступна
+		{
+			name:         "Database down",
+			target:       "/readyz",
+			dbErr:        errors.New("connection refused"),
+			expectedCode: http.StatusServiceUnavailable,
+		},
+		// Тест 4: Кэш недоступен
+		{
+			name:         "Cache down",
+			target:       "/readyz",
+			cacheErr:     errors.New("connection refused"),
+			expectedCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDatabase(t)
+			mockCache := mocks.NewCache(t)
+
+			if tc.target == "/readyz" {
+				mockDB.On("Ping", mock.Anything).Return(tc.dbErr).Once()
+				if tc.dbErr == nil {
+					mockCache.On("Ping", mock.Anything).Return(tc.cacheErr).Once()
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			rr := httptest.NewRecorder()
+
+			New(mockDB, mockCache, testKeys, testConfig.Auth).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+			} else {
+				var response apierror.Response
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+				assert.Equal(t, apierror.CodeUnavailable, response.Code)
+				assert.NotContains(t, response.Message, "connection refused")
+			}
+		})
+	}
+}
+
+func TestStorageGetsRequestContext(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	mockCache := mocks.NewCache(t)
+
+	// Тест 1: Хранилище получает контекст запроса, а не context.Background
+	requestCtx := mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(`requestId`) == `context-test`
+	})
+	mockDB.On("GetUserByEmail", requestCtx, "test@gmail.com").Return(models.User{}, storage.ErrNotFound).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email": "test@gmail.com", "password": "secret"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-Id", "context-test")
+
+	rr := httptest.NewRecorder()
+	New(mockDB, mockCache, testKeys, testConfig.Auth).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Тест 2: Отмена запроса клиентом отменяет контекст хранилища
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mockDB.On("GetUserByEmail", mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Err() != nil
+	}), "test@gmail.com").Return(models.User{}, context.Canceled).Once()
+
+	req = httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email": "test@gmail.com", "password": "secret"}`)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	rr = httptest.NewRecorder()
+	New(mockDB, mockCache, testKeys, testConfig.Auth).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -0,0 +1,155 @@
+package router
+
+import (
+	"avitoBootcamp/internal/apierror"
+	"avitoBootcamp/internal/storage/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type specOperation struct {
+	Responses map[string]any `yaml:"responses"`
+}
+
+type spec struct {
+	Paths map[string]map[string]yaml.Node `yaml:"paths"`
+}
+
+// loadSpecOperations reads api.yaml and returns its operations by "METHOD path".
+func loadSpecOperations(t *testing.T) map[string]specOperation {
+	data, err := os.ReadFile(`../../api.yaml`)
+	require.NoError(t, err)
+
+	var document spec
+	require.NoError(t, yaml.Unmarshal(data, &document))
+
+	operations := map[string]specOperation{}
+
+	for path, item := range document.Paths {
+		for method, node := range item {
+			switch method {
+			case `get`, `post`, `put`, `patch`, `delete`:
+			default:
+				continue
+			}
+
+			var operation specOperation
+			require.NoError(t, node.Decode(&operation))
+
+			operations[strings.ToUpper(method)+` `+path] = operation
+		}
+	}
+
+	return operations
+}
+
+func routeOperations(t *testing.T) []string {
+	var operations []string
+
+	err := newRouter(mocks.NewDatabase(t), mocks.NewCache(t), testKeys, testConfig.Auth).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+
+		for _, method := range methods {
+			operations = append(operations, method+` `+path)
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	sort.Strings(operations)
+
+	return operations
+}
+
+func TestRoutesMatchSpec(t *testing.T) {
+	specOperations := loadSpecOperations(t)
+	operations := routeOperations(t)
+
+	// Тест 1: Каждый маршрут описан в спецификации
+	for _, operation := range operations {
+		assert.Contains(t, specOperations, operation)
+	}
+
+	// Тест 2: Каждая операция спецификации обслуживается роутером
+	for operation := range specOperations {
+		assert.Contains(t, operations, operation)
+	}
+}
+
+func TestRouteErrorsMatchSpec(t *testing.T) {
+	specOperations := loadSpecOperations(t)
+
+	for _, operation := range routeOperations(t) {
+		// The probes answer without a token and are covered by TestProbeHandlers.
+		if operation == `GET /healthz` || operation == `GET /readyz` {
+			continue
+		}
+
+		t.Run(operation, func(t *testing.T) {
+			method, path, _ := strings.Cut(operation, ` `)
+			target := strings.NewReplacer(`{id}`, `1`, `{code}`, `test`).Replace(path)
+
+			// Тест 1: Запрос без токена (или без обязательных данных для
+			// открытых маршрутов) получает ошибку, описанную в спецификации
+			req := httptest.NewRequest(method, target, nil)
+			rr := httptest.NewRecorder()
+
+			New(mocks.NewDatabase(t), mocks.NewCache(t), testKeys, testConfig.Auth).ServeHTTP(rr, req)
+
+			assert.Contains(t, specOperations[operation].Responses, strconv.Itoa(rr.Code))
+			assert.Empty(t, rr.Header().Get(`Retry-After`))
+			assertErrorBody(t, rr)
+		})
+	}
+
+	// Тест 2: Неизвестный маршрут и неподдерживаемый метод тоже получают тело ошибки
+	for _, tc := range []struct {
+		method       string
+		target       string
+		expectedCode int
+	}{
+		{method: http.MethodGet, target: `/no/such/route`, expectedCode: apierror.CodeRouteNotFound},
+		{method: http.MethodPut, target: `/house/1`, expectedCode: apierror.CodeMethodNotAllowed},
+	} {
+		req := httptest.NewRequest(tc.method, tc.target, nil)
+		rr := httptest.NewRecorder()
+
+		New(mocks.NewDatabase(t), mocks.NewCache(t), testKeys, testConfig.Auth).ServeHTTP(rr, req)
+
+		response := assertErrorBody(t, rr)
+		assert.Equal(t, tc.expectedCode, response.Code)
+	}
+}
+
+func assertErrorBody(t *testing.T, rr *httptest.ResponseRecorder) apierror.Response {
+	assert.Equal(t, `application/json`, rr.Header().Get(`Content-Type`))
+
+	var response apierror.Response
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.Message)
+	assert.NotZero(t, response.Code)
+	assert.NotEmpty(t, response.RequestId)
+	assert.Equal(t, rr.Header().Get(`X-Request-Id`), response.RequestId)
+
+	return response
+}
@@ -0,0 +1,31 @@
+package sender
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+type Sender interface {
+	SendEmail(ctx context.Context, recipient string, message string) error
+}
+
+// LogSender is a stand-in for a real mail service: every email is written
+// as a structured log line to the given writer (a file, stdout, etc.).
+type LogSender struct {
+	logger *slog.Logger
+}
+
+func New(w io.Writer) *LogSender {
+	return &LogSender{logger: slog.New(slog.NewJSONHandler(w, nil))}
+}
+
+func (s *LogSender) SendEmail(ctx context.Context, recipient string, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "Sending email", "recipient", recipient, "message", message)
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package storage
+
+import "errors"
+
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+	ErrEmailTaken         = errors.New("email is already registered")
+
+	ErrInvalidStatus           = errors.New("invalid flat status")
+	ErrInvalidStatusTransition = errors.New("flat status transition is not allowed")
+	ErrFlatLocked              = errors.New("flat is being moderated by another moderator")
+	ErrInvalidDeclineReason    = errors.New("unknown decline reason")
+	ErrNotFlatOwner            = errors.New("flat belongs to another user")
+	ErrFlatNumberTaken         = errors.New("flat number is already taken in this house")
+	ErrHouseDeleted            = errors.New("house is archived")
+	ErrHouseNotFound           = errors.New("house not found")
+)
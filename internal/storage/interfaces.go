@@ -2,22 +2,54 @@ package storage
 
 import (
 	"avitoBootcamp/internal/models"
+	"context"
+	"time"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.44.2 --name=database
 type Database interface {
-	GetFlatsByHouseID(houseId int64, userType string) ([]models.Flat, error)
-	CreateFlat(flat models.Flat) (models.Flat, error)
-	UpdateAtHouseLastFlatTime(houseId int64) error
-	CreateHouse(house models.House) (models.House, error)
-	UpdateFlat(flat models.Flat) (models.Flat, error)
-	CreateUser(user models.User) (models.User, error)
-	GetUserById(id string) (models.User, error)
+	GetFlatsByHouseID(ctx context.Context, houseId int64, userType string, query models.HouseFlatsQuery) (models.HouseFlatsPage, error)
+	GetFlatById(ctx context.Context, id int64) (models.Flat, error)
+	GetFlatsByOwner(ctx context.Context, ownerId string) ([]models.Flat, error)
+	SearchFlats(ctx context.Context, filter models.FlatSearchFilter, userType string) (models.FlatSearchPage, error)
+	CreateFlat(ctx context.Context, flat models.Flat) (models.Flat, error)
+	CreateHouse(ctx context.Context, house models.House) (models.House, error)
+	GetHouseById(ctx context.Context, houseId int64) (models.House, error)
+	UpdateHouse(ctx context.Context, houseId int64, edit models.HouseEdit) (models.House, error)
+	GetHouses(ctx context.Context, filter models.HouseFilter) (models.HousePage, error)
+	DeleteHouse(ctx context.Context, houseId int64) (models.House, error)
+	RestoreHouse(ctx context.Context, houseId int64) (models.House, error)
+	DeleteFlat(ctx context.Context, flatId int64) (models.Flat, error)
+	RestoreFlat(ctx context.Context, flatId int64) (models.Flat, error)
+	UpdateFlat(ctx context.Context, flat models.Flat) (models.Flat, error)
+	EditFlat(ctx context.Context, flatId int64, ownerId string, edit models.FlatEdit) (models.Flat, error)
+	ResubmitFlat(ctx context.Context, flatId int64, ownerId string, edit models.FlatEdit) (models.Flat, error)
+	GetFlatStatusHistory(ctx context.Context, flatId int64) ([]models.FlatStatusChange, error)
+	GetModerationQueue(ctx context.Context, filter models.ModerationQueueFilter) (models.ModerationQueuePage, error)
+	TakeNextFlatForModeration(ctx context.Context, moderatorId string, filter models.ModerationQueueFilter) (models.Flat, error)
+	GetDeclineReasons(ctx context.Context) ([]models.DeclineReason, error)
+	SaveDeclineReason(ctx context.Context, reason models.DeclineReason) (models.DeclineReason, error)
+	DeactivateDeclineReason(ctx context.Context, code string) error
+	CreateUser(ctx context.Context, user models.User) (models.User, error)
+	GetUserById(ctx context.Context, id string) (models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+	CreateRefreshToken(ctx context.Context, token models.RefreshToken) error
+	RotateRefreshToken(ctx context.Context, oldHash string, newToken models.RefreshToken) (models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, hash string, userId string) error
+	CreateSubscription(ctx context.Context, subscription models.Subscription) (models.Subscription, error)
+	GetSubscriptionsByHouseID(ctx context.Context, houseId int64) ([]models.Subscription, error)
+	ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error)
+	CompleteOutboxEvent(ctx context.Context, id int64) error
+	FailOutboxEvent(ctx context.Context, id int64, nextAttemptAt time.Time, reason string) error
+	Ping(ctx context.Context) error
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.44.2 --name=cache
 type Cache interface {
-	PutFlatsByHouseID(flats []models.Flat, houseId int64, userType string) error
-	GetFlatsByHouseID(houseId int64, userType string) ([]byte, error)
-	DeleteFlatsByHouseId(houseId int64, userType string)
+	PutFlatsByHouseID(ctx context.Context, page models.HouseFlatsPage, houseId int64, userType string, queryKey string) error
+	GetFlatsByHouseID(ctx context.Context, houseId int64, userType string, queryKey string) ([]byte, error)
+	DeleteFlatsByHouseId(ctx context.Context, houseId int64, userType string)
+	RevokeToken(ctx context.Context, tokenId string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, tokenId string) (bool, error)
+	Ping(ctx context.Context) error
 }
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+	time "time"
+
 	models "avitoBootcamp/internal/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,14 +16,14 @@ type Cache struct {
 	mock.Mock
 }
 
-// DeleteFlatsByHouseId provides a mock function with given fields: houseId, userType
-func (_m *Cache) DeleteFlatsByHouseId(houseId int64, userType string) {
-	_m.Called(houseId, userType)
+// DeleteFlatsByHouseId provides a mock function with given fields: ctx, houseId, userType
+func (_m *Cache) DeleteFlatsByHouseId(ctx context.Context, houseId int64, userType string) {
+	_m.Called(ctx, houseId, userType)
 }
 
-// GetFlatsByHouseID provides a mock function with given fields: houseId, userType
-func (_m *Cache) GetFlatsByHouseID(houseId int64, userType string) ([]byte, error) {
-	ret := _m.Called(houseId, userType)
+// GetFlatsByHouseID provides a mock function with given fields: ctx, houseId, userType, queryKey
+func (_m *Cache) GetFlatsByHouseID(ctx context.Context, houseId int64, userType string, queryKey string) ([]byte, error) {
+	ret := _m.Called(ctx, houseId, userType, queryKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetFlatsByHouseID")
@@ -28,19 +31,19 @@ func (_m *Cache) GetFlatsByHouseID(houseId int64, userType string) ([]byte, erro
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, string) ([]byte, error)); ok {
-		return rf(houseId, userType)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) ([]byte, error)); ok {
+		return rf(ctx, houseId, userType, queryKey)
 	}
-	if rf, ok := ret.Get(0).(func(int64, string) []byte); ok {
-		r0 = rf(houseId, userType)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) []byte); ok {
+		r0 = rf(ctx, houseId, userType, queryKey)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
-		r1 = rf(houseId, userType)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, houseId, userType, queryKey)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -48,17 +51,81 @@ func (_m *Cache) GetFlatsByHouseID(houseId int64, userType string) ([]byte, erro
 	return r0, r1
 }
 
-// PutFlatsByHouseID provides a mock function with given fields: flats, houseId, userType
-func (_m *Cache) PutFlatsByHouseID(flats []models.Flat, houseId int64, userType string) error {
-	ret := _m.Called(flats, houseId, userType)
+// IsTokenRevoked provides a mock function with given fields: ctx, tokenId
+func (_m *Cache) IsTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	ret := _m.Called(ctx, tokenId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, tokenId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, tokenId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *Cache) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PutFlatsByHouseID provides a mock function with given fields: ctx, page, houseId, userType, queryKey
+func (_m *Cache) PutFlatsByHouseID(ctx context.Context, page models.HouseFlatsPage, houseId int64, userType string, queryKey string) error {
+	ret := _m.Called(ctx, page, houseId, userType, queryKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PutFlatsByHouseID")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]models.Flat, int64, string) error); ok {
-		r0 = rf(flats, houseId, userType)
+	if rf, ok := ret.Get(0).(func(context.Context, models.HouseFlatsPage, int64, string, string) error); ok {
+		r0 = rf(ctx, page, houseId, userType, queryKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeToken provides a mock function with given fields: ctx, tokenId, ttl
+func (_m *Cache) RevokeToken(ctx context.Context, tokenId string, ttl time.Duration) error {
+	ret := _m.Called(ctx, tokenId, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, tokenId, ttl)
 	} else {
 		r0 = ret.Error(0)
 	}
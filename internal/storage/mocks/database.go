@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+	time "time"
+
 	models "avitoBootcamp/internal/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,9 +16,57 @@ type Database struct {
 	mock.Mock
 }
 
-// CreateFlat provides a mock function with given fields: flat
-func (_m *Database) CreateFlat(flat models.Flat) (models.Flat, error) {
-	ret := _m.Called(flat)
+// ClaimOutboxEvents provides a mock function with given fields: ctx, limit, lease
+func (_m *Database) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	ret := _m.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimOutboxEvents")
+	}
+
+	var r0 []models.OutboxEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]models.OutboxEvent, error)); ok {
+		return rf(ctx, limit, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) []models.OutboxEvent); ok {
+		r0 = rf(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = rf(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteOutboxEvent provides a mock function with given fields: ctx, id
+func (_m *Database) CompleteOutboxEvent(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteOutboxEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateFlat provides a mock function with given fields: ctx, flat
+func (_m *Database) CreateFlat(ctx context.Context, flat models.Flat) (models.Flat, error) {
+	ret := _m.Called(ctx, flat)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateFlat")
@@ -23,17 +74,17 @@ func (_m *Database) CreateFlat(flat models.Flat) (models.Flat, error) {
 
 	var r0 models.Flat
 	var r1 error
-	if rf, ok := ret.Get(0).(func(models.Flat) (models.Flat, error)); ok {
-		return rf(flat)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Flat) (models.Flat, error)); ok {
+		return rf(ctx, flat)
 	}
-	if rf, ok := ret.Get(0).(func(models.Flat) models.Flat); ok {
-		r0 = rf(flat)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Flat) models.Flat); ok {
+		r0 = rf(ctx, flat)
 	} else {
 		r0 = ret.Get(0).(models.Flat)
 	}
 
-	if rf, ok := ret.Get(1).(func(models.Flat) error); ok {
-		r1 = rf(flat)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Flat) error); ok {
+		r1 = rf(ctx, flat)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,9 +92,9 @@ func (_m *Database) CreateFlat(flat models.Flat) (models.Flat, error) {
 	return r0, r1
 }
 
-// CreateHouse provides a mock function with given fields: house
-func (_m *Database) CreateHouse(house models.House) (models.House, error) {
-	ret := _m.Called(house)
+// CreateHouse provides a mock function with given fields: ctx, house
+func (_m *Database) CreateHouse(ctx context.Context, house models.House) (models.House, error) {
+	ret := _m.Called(ctx, house)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateHouse")
@@ -51,17 +102,17 @@ func (_m *Database) CreateHouse(house models.House) (models.House, error) {
 
 	var r0 models.House
 	var r1 error
-	if rf, ok := ret.Get(0).(func(models.House) (models.House, error)); ok {
-		return rf(house)
+	if rf, ok := ret.Get(0).(func(context.Context, models.House) (models.House, error)); ok {
+		return rf(ctx, house)
 	}
-	if rf, ok := ret.Get(0).(func(models.House) models.House); ok {
-		r0 = rf(house)
+	if rf, ok := ret.Get(0).(func(context.Context, models.House) models.House); ok {
+		r0 = rf(ctx, house)
 	} else {
 		r0 = ret.Get(0).(models.House)
 	}
 
-	if rf, ok := ret.Get(1).(func(models.House) error); ok {
-		r1 = rf(house)
+	if rf, ok := ret.Get(1).(func(context.Context, models.House) error); ok {
+		r1 = rf(ctx, house)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,9 +120,55 @@ func (_m *Database) CreateHouse(house models.House) (models.House, error) {
 	return r0, r1
 }
 
-// CreateUser provides a mock function with given fields: user
-func (_m *Database) CreateUser(user models.User) (models.User, error) {
-	ret := _m.Called(user)
+// CreateRefreshToken provides a mock function with given fields: ctx, token
+func (_m *Database) CreateRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.RefreshToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSubscription provides a mock function with given fields: ctx, subscription
+func (_m *Database) CreateSubscription(ctx context.Context, subscription models.Subscription) (models.Subscription, error) {
+	ret := _m.Called(ctx, subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSubscription")
+	}
+
+	var r0 models.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Subscription) (models.Subscription, error)); ok {
+		return rf(ctx, subscription)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Subscription) models.Subscription); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		r0 = ret.Get(0).(models.Subscription)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Subscription) error); ok {
+		r1 = rf(ctx, subscription)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateUser provides a mock function with given fields: ctx, user
+func (_m *Database) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
@@ -79,17 +176,167 @@ func (_m *Database) CreateUser(user models.User) (models.User, error) {
 
 	var r0 models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(models.User) (models.User, error)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, models.User) (models.User, error)); ok {
+		return rf(ctx, user)
 	}
-	if rf, ok := ret.Get(0).(func(models.User) models.User); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, models.User) models.User); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Get(0).(models.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(models.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, models.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeactivateDeclineReason provides a mock function with given fields: ctx, code
+func (_m *Database) DeactivateDeclineReason(ctx context.Context, code string) error {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateDeclineReason")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteFlat provides a mock function with given fields: ctx, flatId
+func (_m *Database) DeleteFlat(ctx context.Context, flatId int64) (models.Flat, error) {
+	ret := _m.Called(ctx, flatId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFlat")
+	}
+
+	var r0 models.Flat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Flat, error)); ok {
+		return rf(ctx, flatId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Flat); ok {
+		r0 = rf(ctx, flatId)
+	} else {
+		r0 = ret.Get(0).(models.Flat)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, flatId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteHouse provides a mock function with given fields: ctx, houseId
+func (_m *Database) DeleteHouse(ctx context.Context, houseId int64) (models.House, error) {
+	ret := _m.Called(ctx, houseId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteHouse")
+	}
+
+	var r0 models.House
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.House, error)); ok {
+		return rf(ctx, houseId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.House); ok {
+		r0 = rf(ctx, houseId)
+	} else {
+		r0 = ret.Get(0).(models.House)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, houseId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EditFlat provides a mock function with given fields: ctx, flatId, ownerId, edit
+func (_m *Database) EditFlat(ctx context.Context, flatId int64, ownerId string, edit models.FlatEdit) (models.Flat, error) {
+	ret := _m.Called(ctx, flatId, ownerId, edit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditFlat")
+	}
+
+	var r0 models.Flat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.FlatEdit) (models.Flat, error)); ok {
+		return rf(ctx, flatId, ownerId, edit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.FlatEdit) models.Flat); ok {
+		r0 = rf(ctx, flatId, ownerId, edit)
+	} else {
+		r0 = ret.Get(0).(models.Flat)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, models.FlatEdit) error); ok {
+		r1 = rf(ctx, flatId, ownerId, edit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FailOutboxEvent provides a mock function with given fields: ctx, id, nextAttemptAt, reason
+func (_m *Database) FailOutboxEvent(ctx context.Context, id int64, nextAttemptAt time.Time, reason string) error {
+	ret := _m.Called(ctx, id, nextAttemptAt, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailOutboxEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, string) error); ok {
+		r0 = rf(ctx, id, nextAttemptAt, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeclineReasons provides a mock function with given fields: ctx
+func (_m *Database) GetDeclineReasons(ctx context.Context) ([]models.DeclineReason, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeclineReasons")
+	}
+
+	var r0 []models.DeclineReason
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.DeclineReason, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.DeclineReason); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DeclineReason)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -97,29 +344,115 @@ func (_m *Database) CreateUser(user models.User) (models.User, error) {
 	return r0, r1
 }
 
-// GetFlatsByHouseID provides a mock function with given fields: houseId, userType
-func (_m *Database) GetFlatsByHouseID(houseId int64, userType string) ([]models.Flat, error) {
-	ret := _m.Called(houseId, userType)
+// GetFlatById provides a mock function with given fields: ctx, id
+func (_m *Database) GetFlatById(ctx context.Context, id int64) (models.Flat, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFlatById")
+	}
+
+	var r0 models.Flat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Flat, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Flat); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Flat)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFlatStatusHistory provides a mock function with given fields: ctx, flatId
+func (_m *Database) GetFlatStatusHistory(ctx context.Context, flatId int64) ([]models.FlatStatusChange, error) {
+	ret := _m.Called(ctx, flatId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFlatStatusHistory")
+	}
+
+	var r0 []models.FlatStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.FlatStatusChange, error)); ok {
+		return rf(ctx, flatId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.FlatStatusChange); ok {
+		r0 = rf(ctx, flatId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.FlatStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, flatId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFlatsByHouseID provides a mock function with given fields: ctx, houseId, userType, query
+func (_m *Database) GetFlatsByHouseID(ctx context.Context, houseId int64, userType string, query models.HouseFlatsQuery) (models.HouseFlatsPage, error) {
+	ret := _m.Called(ctx, houseId, userType, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetFlatsByHouseID")
 	}
 
+	var r0 models.HouseFlatsPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.HouseFlatsQuery) (models.HouseFlatsPage, error)); ok {
+		return rf(ctx, houseId, userType, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.HouseFlatsQuery) models.HouseFlatsPage); ok {
+		r0 = rf(ctx, houseId, userType, query)
+	} else {
+		r0 = ret.Get(0).(models.HouseFlatsPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, models.HouseFlatsQuery) error); ok {
+		r1 = rf(ctx, houseId, userType, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFlatsByOwner provides a mock function with given fields: ctx, ownerId
+func (_m *Database) GetFlatsByOwner(ctx context.Context, ownerId string) ([]models.Flat, error) {
+	ret := _m.Called(ctx, ownerId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFlatsByOwner")
+	}
+
 	var r0 []models.Flat
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, string) ([]models.Flat, error)); ok {
-		return rf(houseId, userType)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Flat, error)); ok {
+		return rf(ctx, ownerId)
 	}
-	if rf, ok := ret.Get(0).(func(int64, string) []models.Flat); ok {
-		r0 = rf(houseId, userType)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Flat); ok {
+		r0 = rf(ctx, ownerId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Flat)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
-		r1 = rf(houseId, userType)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ownerId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -127,9 +460,151 @@ func (_m *Database) GetFlatsByHouseID(houseId int64, userType string) ([]models.
 	return r0, r1
 }
 
-// GetUserById provides a mock function with given fields: id
-func (_m *Database) GetUserById(id string) (models.User, error) {
-	ret := _m.Called(id)
+// GetHouseById provides a mock function with given fields: ctx, houseId
+func (_m *Database) GetHouseById(ctx context.Context, houseId int64) (models.House, error) {
+	ret := _m.Called(ctx, houseId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHouseById")
+	}
+
+	var r0 models.House
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.House, error)); ok {
+		return rf(ctx, houseId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.House); ok {
+		r0 = rf(ctx, houseId)
+	} else {
+		r0 = ret.Get(0).(models.House)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, houseId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHouses provides a mock function with given fields: ctx, filter
+func (_m *Database) GetHouses(ctx context.Context, filter models.HouseFilter) (models.HousePage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHouses")
+	}
+
+	var r0 models.HousePage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.HouseFilter) (models.HousePage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.HouseFilter) models.HousePage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(models.HousePage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.HouseFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetModerationQueue provides a mock function with given fields: ctx, filter
+func (_m *Database) GetModerationQueue(ctx context.Context, filter models.ModerationQueueFilter) (models.ModerationQueuePage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetModerationQueue")
+	}
+
+	var r0 models.ModerationQueuePage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ModerationQueueFilter) (models.ModerationQueuePage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ModerationQueueFilter) models.ModerationQueuePage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(models.ModerationQueuePage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ModerationQueueFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionsByHouseID provides a mock function with given fields: ctx, houseId
+func (_m *Database) GetSubscriptionsByHouseID(ctx context.Context, houseId int64) ([]models.Subscription, error) {
+	ret := _m.Called(ctx, houseId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptionsByHouseID")
+	}
+
+	var r0 []models.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Subscription, error)); ok {
+		return rf(ctx, houseId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Subscription); ok {
+		r0 = rf(ctx, houseId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, houseId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByEmail provides a mock function with given fields: ctx, email
+func (_m *Database) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByEmail")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserById provides a mock function with given fields: ctx, id
+func (_m *Database) GetUserById(ctx context.Context, id string) (models.User, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserById")
@@ -137,17 +612,17 @@ func (_m *Database) GetUserById(id string) (models.User, error) {
 
 	var r0 models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (models.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.User, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(string) models.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(models.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -155,17 +630,17 @@ func (_m *Database) GetUserById(id string) (models.User, error) {
 	return r0, r1
 }
 
-// UpdateAtHouseLastFlatTime provides a mock function with given fields: houseId
-func (_m *Database) UpdateAtHouseLastFlatTime(houseId int64) error {
-	ret := _m.Called(houseId)
+// Ping provides a mock function with given fields: ctx
+func (_m *Database) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateAtHouseLastFlatTime")
+		panic("no return value specified for Ping")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(houseId)
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -173,9 +648,223 @@ func (_m *Database) UpdateAtHouseLastFlatTime(houseId int64) error {
 	return r0
 }
 
-// UpdateFlat provides a mock function with given fields: flat
-func (_m *Database) UpdateFlat(flat models.Flat) (models.Flat, error) {
-	ret := _m.Called(flat)
+// RestoreFlat provides a mock function with given fields: ctx, flatId
+func (_m *Database) RestoreFlat(ctx context.Context, flatId int64) (models.Flat, error) {
+	ret := _m.Called(ctx, flatId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreFlat")
+	}
+
+	var r0 models.Flat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Flat, error)); ok {
+		return rf(ctx, flatId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Flat); ok {
+		r0 = rf(ctx, flatId)
+	} else {
+		r0 = ret.Get(0).(models.Flat)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, flatId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreHouse provides a mock function with given fields: ctx, houseId
+func (_m *Database) RestoreHouse(ctx context.Context, houseId int64) (models.House, error) {
+	ret := _m.Called(ctx, houseId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreHouse")
+	}
+
+	var r0 models.House
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.House, error)); ok {
+		return rf(ctx, houseId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.House); ok {
+		r0 = rf(ctx, houseId)
+	} else {
+		r0 = ret.Get(0).(models.House)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, houseId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResubmitFlat provides a mock function with given fields: ctx, flatId, ownerId, edit
+func (_m *Database) ResubmitFlat(ctx context.Context, flatId int64, ownerId string, edit models.FlatEdit) (models.Flat, error) {
+	ret := _m.Called(ctx, flatId, ownerId, edit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResubmitFlat")
+	}
+
+	var r0 models.Flat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.FlatEdit) (models.Flat, error)); ok {
+		return rf(ctx, flatId, ownerId, edit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.FlatEdit) models.Flat); ok {
+		r0 = rf(ctx, flatId, ownerId, edit)
+	} else {
+		r0 = ret.Get(0).(models.Flat)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, models.FlatEdit) error); ok {
+		r1 = rf(ctx, flatId, ownerId, edit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRefreshToken provides a mock function with given fields: ctx, hash, userId
+func (_m *Database) RevokeRefreshToken(ctx context.Context, hash string, userId string) error {
+	ret := _m.Called(ctx, hash, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, hash, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateRefreshToken provides a mock function with given fields: ctx, oldHash, newToken
+func (_m *Database) RotateRefreshToken(ctx context.Context, oldHash string, newToken models.RefreshToken) (models.RefreshToken, error) {
+	ret := _m.Called(ctx, oldHash, newToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 models.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.RefreshToken) (models.RefreshToken, error)); ok {
+		return rf(ctx, oldHash, newToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.RefreshToken) models.RefreshToken); ok {
+		r0 = rf(ctx, oldHash, newToken)
+	} else {
+		r0 = ret.Get(0).(models.RefreshToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.RefreshToken) error); ok {
+		r1 = rf(ctx, oldHash, newToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveDeclineReason provides a mock function with given fields: ctx, reason
+func (_m *Database) SaveDeclineReason(ctx context.Context, reason models.DeclineReason) (models.DeclineReason, error) {
+	ret := _m.Called(ctx, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDeclineReason")
+	}
+
+	var r0 models.DeclineReason
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.DeclineReason) (models.DeclineReason, error)); ok {
+		return rf(ctx, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.DeclineReason) models.DeclineReason); ok {
+		r0 = rf(ctx, reason)
+	} else {
+		r0 = ret.Get(0).(models.DeclineReason)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.DeclineReason) error); ok {
+		r1 = rf(ctx, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchFlats provides a mock function with given fields: ctx, filter, userType
+func (_m *Database) SearchFlats(ctx context.Context, filter models.FlatSearchFilter, userType string) (models.FlatSearchPage, error) {
+	ret := _m.Called(ctx, filter, userType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchFlats")
+	}
+
+	var r0 models.FlatSearchPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.FlatSearchFilter, string) (models.FlatSearchPage, error)); ok {
+		return rf(ctx, filter, userType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.FlatSearchFilter, string) models.FlatSearchPage); ok {
+		r0 = rf(ctx, filter, userType)
+	} else {
+		r0 = ret.Get(0).(models.FlatSearchPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.FlatSearchFilter, string) error); ok {
+		r1 = rf(ctx, filter, userType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TakeNextFlatForModeration provides a mock function with given fields: ctx, moderatorId, filter
+func (_m *Database) TakeNextFlatForModeration(ctx context.Context, moderatorId string, filter models.ModerationQueueFilter) (models.Flat, error) {
+	ret := _m.Called(ctx, moderatorId, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakeNextFlatForModeration")
+	}
+
+	var r0 models.Flat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.ModerationQueueFilter) (models.Flat, error)); ok {
+		return rf(ctx, moderatorId, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.ModerationQueueFilter) models.Flat); ok {
+		r0 = rf(ctx, moderatorId, filter)
+	} else {
+		r0 = ret.Get(0).(models.Flat)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.ModerationQueueFilter) error); ok {
+		r1 = rf(ctx, moderatorId, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateFlat provides a mock function with given fields: ctx, flat
+func (_m *Database) UpdateFlat(ctx context.Context, flat models.Flat) (models.Flat, error) {
+	ret := _m.Called(ctx, flat)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateFlat")
@@ -183,17 +872,45 @@ func (_m *Database) UpdateFlat(flat models.Flat) (models.Flat, error) {
 
 	var r0 models.Flat
 	var r1 error
-	if rf, ok := ret.Get(0).(func(models.Flat) (models.Flat, error)); ok {
-		return rf(flat)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Flat) (models.Flat, error)); ok {
+		return rf(ctx, flat)
 	}
-	if rf, ok := ret.Get(0).(func(models.Flat) models.Flat); ok {
-		r0 = rf(flat)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Flat) models.Flat); ok {
+		r0 = rf(ctx, flat)
 	} else {
 		r0 = ret.Get(0).(models.Flat)
 	}
 
-	if rf, ok := ret.Get(1).(func(models.Flat) error); ok {
-		r1 = rf(flat)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Flat) error); ok {
+		r1 = rf(ctx, flat)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateHouse provides a mock function with given fields: ctx, houseId, edit
+func (_m *Database) UpdateHouse(ctx context.Context, houseId int64, edit models.HouseEdit) (models.House, error) {
+	ret := _m.Called(ctx, houseId, edit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateHouse")
+	}
+
+	var r0 models.House
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.HouseEdit) (models.House, error)); ok {
+		return rf(ctx, houseId, edit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.HouseEdit) models.House); ok {
+		r0 = rf(ctx, houseId, edit)
+	} else {
+		r0 = ret.Get(0).(models.House)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.HouseEdit) error); ok {
+		r1 = rf(ctx, houseId, edit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,320 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationLockId is the advisory lock held while migrating, so replicas
+// started at the same time apply every migration once.
+const migrationLockId = 7205431094
+
+var (
+	//go:embed migrations/*.sql
+	migrationFiles embed.FS
+
+	//go:embed seed/seed.sql
+	seedQuery string
+
+	migrationNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+	ErrChecksumMismatch = errors.New("applied migration differs from the embedded one")
+	ErrUnknownMigration = errors.New("database has a migration this binary does not know")
+)
+
+// Migration is a versioned schema change with the SQL to apply and to revert it.
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationState is a known migration and, if it is applied, when that happened.
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	files, err := fs.ReadDir(migrationFiles, `migrations`)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+
+	for _, file := range files {
+		match := migrationNamePattern.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", file.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+
+		query, err := fs.ReadFile(migrationFiles, path.Join(`migrations`, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if match[3] == `up` {
+			migration.Up = string(query)
+		} else {
+			migration.Down = string(query)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, migration := range byVersion {
+		if migration.Up == `` || migration.Down == `` {
+			return nil, fmt.Errorf("migration %d needs both an up and a down file", migration.Version)
+		}
+
+		migration.Checksum = migrationChecksum(migration.Up, migration.Down)
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration %d is missing", i+1)
+		}
+	}
+
+	return migrations, nil
+}
+
+// migrationChecksum covers both directions, an edited down file is as much a
+// change of the migration as an edited up file.
+func migrationChecksum(up string, down string) string {
+	hash := sha256.New()
+
+	for _, query := range []string{up, down} {
+		// The length keeps the boundary between the files in the hash.
+		fmt.Fprintf(hash, "%d\n%s", len(query), query)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// MigrateUp applies the migrations the database does not have yet. The
+// checksums of the applied ones are checked first, so an edited migration is
+// reported instead of silently skipped.
+func (storage *Storage) MigrateUp(ctx context.Context) error {
+	return storage.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		states, err := migrationStates(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, state := range states {
+			if state.AppliedAt != nil {
+				continue
+			}
+
+			if err := applyMigration(ctx, conn, state.Version, state.Up, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+					state.Version, state.Name, state.Checksum)
+				return err
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// MigrateDown reverts the last steps applied migrations.
+func (storage *Storage) MigrateDown(ctx context.Context, steps int) error {
+	return storage.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		states, err := migrationStates(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(states) - 1; i >= 0 && steps > 0; i-- {
+			state := states[i]
+			if state.AppliedAt == nil {
+				continue
+			}
+
+			if err := applyMigration(ctx, conn, state.Version, state.Down, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, state.Version)
+				return err
+			}); err != nil {
+				return err
+			}
+
+			steps--
+		}
+
+		return nil
+	})
+}
+
+// MigrationStatus lists the embedded migrations and which of them are applied.
+func (storage *Storage) MigrationStatus(ctx context.Context) ([]MigrationState, error) {
+	var states []MigrationState
+
+	err := storage.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		var err error
+		states, err = migrationStates(ctx, conn)
+		return err
+	})
+
+	return states, err
+}
+
+// Seed fills an empty database with the demo houses and flats used in
+// development and by the integration tests. A database that already has
+// houses is left as is and false is returned.
+func (storage *Storage) Seed(ctx context.Context) (bool, error) {
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	// Concurrent seeds wait here and then see the houses of the first one.
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE house IN EXCLUSIVE MODE`); err != nil {
+		return false, err
+	}
+
+	var hasHouses bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM house)`).Scan(&hasHouses); err != nil {
+		return false, err
+	}
+
+	if hasHouses {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, seedQuery); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// withMigrationLock waits for the lock as long as ctx allows, cancelling ctx
+// aborts the wait as well as a migration in progress.
+func (storage *Storage) withMigrationLock(ctx context.Context, migrate func(conn *sql.Conn) error) error {
+	// The advisory lock belongs to a session, so everything runs on one connection.
+	conn, err := storage.Db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockId); err != nil {
+		return err
+	}
+
+	// The lock is released even when ctx is already cancelled.
+	defer conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockId)
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		checksum VARCHAR(64) NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return err
+	}
+
+	return migrate(conn)
+}
+
+// migrationStates matches the embedded migrations with the applied ones.
+func migrationStates(ctx context.Context, conn *sql.Conn) ([]MigrationState, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	states := make([]MigrationState, len(migrations))
+	for i, migration := range migrations {
+		states[i] = MigrationState{Migration: migration}
+	}
+
+	for rows.Next() {
+		var version int
+		var checksum string
+		var appliedAt time.Time
+
+		if err := rows.Scan(&version, &checksum, &appliedAt); err != nil {
+			return nil, err
+		}
+
+		if version < 1 || version > len(states) {
+			return nil, fmt.Errorf("%w: version %d", ErrUnknownMigration, version)
+		}
+
+		if states[version-1].Checksum != checksum {
+			return nil, fmt.Errorf("%w: version %d", ErrChecksumMismatch, version)
+		}
+
+		states[version-1].AppliedAt = &appliedAt
+	}
+
+	return states, rows.Err()
+}
+
+// applyMigration runs a migration query and records it in one transaction.
+func applyMigration(ctx context.Context, conn *sql.Conn, version int, query string, record func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("migration %d: %w", version, err)
+	}
+
+	if err := record(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	// Тест 1: Версии идут подряд, у каждой миграции есть up и down
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version)
+		assert.NotEmpty(t, migration.Name)
+		assert.NotEmpty(t, migration.Up)
+		assert.NotEmpty(t, migration.Down)
+		assert.Len(t, migration.Checksum, 64)
+	}
+
+	// Тест 2: Контрольная сумма не меняется между загрузками
+	again, err := Migrations()
+	require.NoError(t, err)
+	assert.Equal(t, migrations, again)
+
+	// Тест 3: Тестовые данные не входят в миграции
+	for _, migration := range migrations {
+		assert.NotContains(t, migration.Up, `INSERT INTO house`)
+	}
+
+	// Тест 4: Контрольная сумма учитывает и up, и down
+	checksum := migrationChecksum(`CREATE TABLE a ();`, `DROP TABLE a;`)
+	assert.NotEqual(t, checksum, migrationChecksum(`CREATE TABLE a ();`, `DROP TABLE IF EXISTS a;`))
+	assert.NotEqual(t, checksum, migrationChecksum(`CREATE TABLE a (); DROP TABLE a;`, ``))
+}
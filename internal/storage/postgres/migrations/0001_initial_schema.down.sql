@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS refresh_token;
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS subscription;
+DROP TABLE IF EXISTS flat_status_history;
+DROP TABLE IF EXISTS flat;
+DROP TABLE IF EXISTS decline_reason;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS house;
+DROP TYPE IF EXISTS flat_status;
+-- pg_trgm is kept, the extension is shared by the whole database.
@@ -0,0 +1,202 @@
+-- The schema as it was created at every startup before migrations existed.
+-- It is idempotent, so databases created that way are adopted as version 1.
+
+-- Flat search matches house addresses by substring.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+DO $$ 
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'flat_status') THEN
+        CREATE TYPE flat_status AS ENUM ('created', 'approved', 'declined', 'on moderation');
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS house (
+    id SERIAL PRIMARY KEY,
+    address VARCHAR(1000) NOT NULL,
+    "year" INTEGER NOT NULL CHECK ("year" >= 0),
+    developer VARCHAR(1000),
+    created_at VARCHAR(255),
+    update_at VARCHAR(255),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) UNIQUE NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    user_type VARCHAR(50) NOT NULL CHECK (user_type IN ('client', 'moderator'))
+);
+
+-- Owners of the tokens issued by /dummyLogin, they can not log in with a password.
+INSERT INTO users (id, email, password_hash, user_type) VALUES
+('00000000-0000-0000-0000-000000000001', 'client@dummy.login', '', 'client'),
+('00000000-0000-0000-0000-000000000002', 'moderator@dummy.login', '', 'moderator')
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS decline_reason (
+    code VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true
+);
+
+INSERT INTO decline_reason (code, description) VALUES
+('incomplete_data', 'Не хватает данных о квартире'),
+('wrong_price', 'Цена не соответствует квартире'),
+('duplicate', 'Квартира уже опубликована'),
+('other', 'Другая причина')
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS flat (
+    id SERIAL PRIMARY KEY,
+    house_id INTEGER NOT NULL REFERENCES house(id),
+    price INTEGER NOT NULL CHECK (price >= 0),
+    rooms INTEGER NOT NULL CHECK (rooms >= 1),
+    flat_num INTEGER NOT NULL CHECK (flat_num >= 1),
+    "status" flat_status,
+    moderator_id UUID REFERENCES users(id),
+    owner_id UUID REFERENCES users(id),
+    moderation_expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    decline_reason_code VARCHAR(64) REFERENCES decline_reason(code),
+    decline_reason TEXT,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT unique_house_flat UNIQUE (house_id, flat_num)
+);
+
+-- moderator_id used to be an arbitrary integer from the request body. Those
+-- numbers can not be mapped to users, so they are dropped and flats that were
+-- on moderation go back to the queue.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'flat' AND column_name = 'moderator_id' AND data_type = 'integer') THEN
+        UPDATE flat SET "status" = 'created' WHERE "status" = 'on moderation';
+        ALTER TABLE flat ALTER COLUMN moderator_id TYPE UUID USING NULL;
+        ALTER TABLE flat ADD CONSTRAINT flat_moderator_id_fkey FOREIGN KEY (moderator_id) REFERENCES users(id);
+    END IF;
+END $$;
+
+-- Flats taken for moderation before the lease existed have no expiry and can
+-- be taken over right away.
+ALTER TABLE flat ADD COLUMN IF NOT EXISTS moderation_expires_at TIMESTAMPTZ;
+
+-- Existing flats get the time of the upgrade as their place in the moderation queue.
+ALTER TABLE flat ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE flat ADD COLUMN IF NOT EXISTS decline_reason_code VARCHAR(64) REFERENCES decline_reason(code);
+ALTER TABLE flat ADD COLUMN IF NOT EXISTS decline_reason TEXT;
+
+-- Archived houses and flats keep their rows and flat numbers so they can be restored.
+ALTER TABLE house ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE flat ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS flat_status_history (
+    id SERIAL PRIMARY KEY,
+    flat_id INTEGER NOT NULL REFERENCES flat(id),
+    actor_id UUID REFERENCES users(id),
+    old_status flat_status,
+    new_status flat_status NOT NULL,
+    reason_code VARCHAR(64) REFERENCES decline_reason(code),
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE flat_status_history ADD COLUMN IF NOT EXISTS reason_code VARCHAR(64) REFERENCES decline_reason(code);
+
+-- Flats created before owner_id existed take their owner from the first
+-- history record, older ones stay without an owner.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'flat' AND column_name = 'owner_id') THEN
+        ALTER TABLE flat ADD COLUMN owner_id UUID REFERENCES users(id);
+        UPDATE flat SET owner_id = h.actor_id FROM flat_status_history h
+        WHERE h.flat_id = flat.id AND h.old_status IS NULL;
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS subscription (
+    id SERIAL PRIMARY KEY,
+    house_id INTEGER NOT NULL REFERENCES house(id),
+    email VARCHAR(255) NOT NULL,
+    created_at VARCHAR(255),
+    CONSTRAINT unique_house_subscription UNIQUE (house_id, email)
+);
+
+CREATE TABLE IF NOT EXISTS refresh_token (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ,
+    replaced_by VARCHAR(64)
+);
+
+CREATE TABLE IF NOT EXISTS outbox (
+    id SERIAL PRIMARY KEY,
+    event_type VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    processed_at TIMESTAMPTZ
+);
+
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relname = 'idx_house_id' AND relkind = 'i') THEN
+        CREATE INDEX idx_house_id ON flat (house_id);
+    END IF;
+END $$;
+
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relname = 'idx_flat_moderation_queue' AND relkind = 'i') THEN
+        CREATE INDEX idx_flat_moderation_queue ON flat (created_at, id) WHERE "status" = 'created';
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relname = 'idx_flat_status_history_flat_id' AND relkind = 'i') THEN
+        CREATE INDEX idx_flat_status_history_flat_id ON flat_status_history (flat_id);
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relname = 'idx_flat_owner_id' AND relkind = 'i') THEN
+        CREATE INDEX idx_flat_owner_id ON flat (owner_id);
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relname = 'idx_house_address_trgm' AND relkind = 'i') THEN
+        CREATE INDEX idx_house_address_trgm ON house USING gin (address gin_trgm_ops);
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relname = 'idx_flat_price' AND relkind = 'i') THEN
+        CREATE INDEX idx_flat_price ON flat (price, id) WHERE deleted_at IS NULL;
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relname = 'idx_outbox_pending' AND relkind = 'i') THEN
+        CREATE INDEX idx_outbox_pending ON outbox (next_attempt_at) WHERE processed_at IS NULL;
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relname = 'idx_refresh_token_family' AND relkind = 'i') THEN
+        CREATE INDEX idx_refresh_token_family ON refresh_token (family_id);
+    END IF;
+END $$;
@@ -1,49 +1,56 @@
 package postgres
 
 import (
+	"avitoBootcamp/internal/config"
 	"avitoBootcamp/internal/models"
+	store "avitoBootcamp/internal/storage"
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"os"
+	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 const (
-	host       = "db"
-	port       = 5432
-	user       = "postgres"
-	password   = "postgres"
-	dbname     = "avitobootcamp"
-	sslmode    = "disable"
 	driverName = "postgres"
-	hostTest   = `localhost`
-	portTest   = 5433
+
+	// timestampLayout is the format of the VARCHAR created_at and update_at columns.
+	timestampLayout = "2006-01-02T15:04:05.000Z"
 )
 
 type Storage struct {
-	Db *sql.DB
+	Db          *sql.DB
+	Transitions models.StatusTransitions
+	// ModerationLease is how long a flat stays locked by the moderator who
+	// took it. After it lapses another moderator may take the flat over.
+	ModerationLease time.Duration
+	// QueryTimeout bounds every call on top of the deadline of its context.
+	QueryTimeout time.Duration
 }
 
-func New() (*Storage, error) {
+// New connects to the database and brings its schema up to date. Demo data
+// is not added, see Seed.
+func New(ctx context.Context, cfg config.Postgres) (*Storage, error) {
 
-	storage, err := Connect()
+	storage, err := Connect(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := storage.init(); err != nil {
+	if err := storage.MigrateUp(ctx); err != nil {
 		return storage, err
 	}
 
 	return storage, nil
 }
 
-func ConnectForTest() (*Storage, error) {
-	databaseName := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%d sslmode=%s", user, password, dbname, hostTest, portTest, sslmode)
-	database, err := sql.Open(driverName, databaseName)
+// Connect connects to the database without touching its schema.
+func Connect(cfg config.Postgres) (*Storage, error) {
+	database, err := sql.Open(driverName, cfg.DSN())
 
 	if err != nil {
 		return nil, err
@@ -53,180 +60,1425 @@ func ConnectForTest() (*Storage, error) {
 		return nil, err
 	}
 
-	return &Storage{Db: database}, nil
+	transitions := models.NewStatusTransitions(models.ReopenRules(cfg.Reopen))
+
+	return &Storage{Db: database, Transitions: transitions, ModerationLease: cfg.ModerationLease, QueryTimeout: cfg.QueryTimeout}, nil
+}
+
+// Ping checks that the database answers, it backs the /readyz probe.
+func (storage *Storage) Ping(ctx context.Context) error {
+	return storage.Db.PingContext(ctx)
+}
+
+// withTimeout applies QueryTimeout, so a slow query is cancelled even when
+// the caller has no deadline.
+func (storage *Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if storage.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, storage.QueryTimeout)
 }
 
-func Connect() (*Storage, error) {
-	databaseName := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%d sslmode=%s", user, password, dbname, host, port, sslmode)
-	database, err := sql.Open(driverName, databaseName)
+// flatColumns are read by scanFlat, every query returning whole flats selects them.
+const flatColumns = `id, house_id, price, rooms, status, flat_num, moderator_id, owner_id, moderation_expires_at,
+COALESCE(decline_reason_code, ''), COALESCE(decline_reason, ''), deleted_at`
 
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFlat(row rowScanner) (models.Flat, error) {
+	var flat models.Flat
+	var moderatorId, ownerId *string
+
+	err := row.Scan(&flat.Id, &flat.HouseId, &flat.Price, &flat.Rooms, &flat.Status, &flat.Num, &moderatorId, &ownerId,
+		&flat.ModerationExpiresAt, &flat.DeclineReasonCode, &flat.DeclineReason, &flat.DeletedAt)
 	if err != nil {
-		return nil, err
+		return flat, err
 	}
 
-	if err := database.Ping(); err != nil {
-		return nil, err
+	if moderatorId != nil {
+		flat.ModeratorId = *moderatorId
+	}
+
+	if ownerId != nil {
+		flat.OwnerId = *ownerId
 	}
 
-	return &Storage{Db: database}, nil
+	return flat, nil
 }
 
-func (storage *Storage) init() error {
-	initQuery, err := storage.readSqlQuery(`tables/createTables.sql`)
+func (storage *Storage) queryFlats(ctx context.Context, query string, args ...any) ([]models.Flat, error) {
+	rows, err := storage.Db.QueryContext(ctx, query, args...)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if _, err := storage.Db.Query(initQuery); err != nil {
-		return err
+	defer rows.Close()
+
+	var flats []models.Flat
+
+	for rows.Next() {
+		currFlat, err := scanFlat(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		flats = append(flats, currFlat)
+	}
+
+	return flats, rows.Err()
+}
+
+// GetFlatsByHouseID returns a page of the house flats. Archived flats are
+// skipped unless query.IncludeDeleted is set, which only moderators may ask for.
+func (storage *Storage) GetFlatsByHouseID(ctx context.Context, houseId int64, userType string, query models.HouseFlatsQuery) (models.HouseFlatsPage, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	page := models.HouseFlatsPage{Flats: []models.Flat{}}
+	conditions := `house_id = $1`
+	args := []any{houseId}
+
+	if userType != `moderator` {
+		conditions += ` AND "status" = 'approved'`
+	}
+
+	if userType != `moderator` || !query.IncludeDeleted {
+		conditions += ` AND deleted_at IS NULL`
+	}
+
+	if query.RoomsMin != nil {
+		args = append(args, *query.RoomsMin)
+		conditions += fmt.Sprintf(` AND rooms >= $%d`, len(args))
+	}
+
+	if query.RoomsMax != nil {
+		args = append(args, *query.RoomsMax)
+		conditions += fmt.Sprintf(` AND rooms <= $%d`, len(args))
+	}
+
+	if query.PriceMin != nil {
+		args = append(args, *query.PriceMin)
+		conditions += fmt.Sprintf(` AND price >= $%d`, len(args))
+	}
+
+	if query.PriceMax != nil {
+		args = append(args, *query.PriceMax)
+		conditions += fmt.Sprintf(` AND price <= $%d`, len(args))
+	}
+
+	// The sort column comes from a fixed list, never from the request as is.
+	column := `flat_num`
+
+	switch query.Sort {
+	case models.HouseFlatsSortPrice:
+		column = `price`
+	case models.HouseFlatsSortRooms:
+		column = `rooms`
+	}
+
+	if query.After != nil {
+		args = append(args, query.After.Value, query.After.Id)
+		conditions += fmt.Sprintf(` AND (%s, id) > ($%d, $%d)`, column, len(args)-1, len(args))
 	}
 
-	fillQuery, err := storage.readSqlQuery(`tables/fillTables.sql`)
+	sqlQuery := `SELECT ` + flatColumns + ` FROM flat WHERE ` + conditions + ` ORDER BY ` + column + `, id`
 
+	// One extra row tells whether there is a next page.
+	if query.Limit > 0 {
+		args = append(args, query.Limit+1)
+		sqlQuery += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	flats, err := storage.queryFlats(ctx, sqlQuery, args...)
 	if err != nil {
-		return err
+		return page, err
 	}
 
-	if _, err := storage.Db.Query(fillQuery); err != nil {
-		return err
+	if query.Limit > 0 && len(flats) > query.Limit {
+		flats = flats[:query.Limit]
+		last := flats[len(flats)-1]
+		page.NextCursor = models.HouseFlatsCursor{Sort: query.Sort, Value: houseFlatsSortValue(last, query.Sort), Id: last.Id}.Encode()
 	}
 
-	return nil
+	if flats != nil {
+		page.Flats = flats
+	}
+
+	return page, nil
+}
+
+func houseFlatsSortValue(flat models.Flat, sort string) int64 {
+	switch sort {
+	case models.HouseFlatsSortPrice:
+		return flat.Price
+	case models.HouseFlatsSortRooms:
+		return int64(flat.Rooms)
+	default:
+		return int64(flat.Num)
+	}
 }
 
-func (storage *Storage) readSqlQuery(source string) (string, error) {
-	file, err := os.Open(source)
+func (storage *Storage) GetFlatById(ctx context.Context, id int64) (models.Flat, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
 
-	if err != nil {
-		return ``, err
+	flat, err := scanFlat(storage.Db.QueryRowContext(ctx, `SELECT `+flatColumns+` FROM flat WHERE id = $1`, id))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return flat, store.ErrNotFound
 	}
 
-	tables, err := io.ReadAll(file)
+	return flat, err
+}
 
-	if err != nil {
-		return ``, err
+// GetFlatsByOwner returns the flats created by the user in all statuses.
+// Archived flats are left out, only a moderator can restore them.
+func (storage *Storage) GetFlatsByOwner(ctx context.Context, ownerId string) ([]models.Flat, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	flats, err := storage.queryFlats(ctx, `SELECT `+flatColumns+` FROM flat WHERE owner_id = $1 AND deleted_at IS NULL ORDER BY id`, ownerId)
+
+	if flats == nil {
+		flats = []models.Flat{}
 	}
 
-	return string(tables), nil
+	return flats, err
 }
 
-func (storage *Storage) GetFlatsByHouseID(houseId int64, userType string) ([]models.Flat, error) {
-	query := `SELECT id, house_id, price, rooms, status, moderator_id, flat_num FROM flat  WHERE house_id = $1 `
+// SearchFlats finds flats across all houses. Like GetFlatsByHouseID it shows
+// only approved flats to clients and never returns archived flats.
+func (storage *Storage) SearchFlats(ctx context.Context, filter models.FlatSearchFilter, userType string) (models.FlatSearchPage, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	page := models.FlatSearchPage{Flats: []models.Flat{}}
+	conditions := `deleted_at IS NULL`
+	houseConditions := `deleted_at IS NULL`
+	var args []any
 
 	if userType != `moderator` {
-		query = `SELECT id, house_id, price, rooms, status, moderator_id, flat_num FROM flat
-		WHERE house_id = $1  AND "status" = 'approved';`
+		conditions += ` AND "status" = 'approved'`
 	}
 
-	rows, err := storage.Db.Query(query, houseId)
+	if filter.PriceMin != nil {
+		args = append(args, *filter.PriceMin)
+		conditions += fmt.Sprintf(` AND price >= $%d`, len(args))
+	}
 
-	if err != nil {
-		return nil, err
+	if filter.PriceMax != nil {
+		args = append(args, *filter.PriceMax)
+		conditions += fmt.Sprintf(` AND price <= $%d`, len(args))
 	}
 
-	defer rows.Close()
+	if filter.RoomsMin != nil {
+		args = append(args, *filter.RoomsMin)
+		conditions += fmt.Sprintf(` AND rooms >= $%d`, len(args))
+	}
 
-	var flats []models.Flat
+	if filter.RoomsMax != nil {
+		args = append(args, *filter.RoomsMax)
+		conditions += fmt.Sprintf(` AND rooms <= $%d`, len(args))
+	}
 
-	for rows.Next() {
-		var currFlat models.Flat
-		var currModeratorId *int
-		if err := rows.Scan(&currFlat.Id, &currFlat.HouseId, &currFlat.Price, &currFlat.Rooms, &currFlat.Status, &currModeratorId, &currFlat.Num); err != nil {
-			return nil, err
-		}
+	if filter.YearMin != nil {
+		args = append(args, *filter.YearMin)
+		houseConditions += fmt.Sprintf(` AND year >= $%d`, len(args))
+	}
 
-		if currModeratorId != nil {
-			currFlat.ModeratorId = *currModeratorId
-		} else {
-			currFlat.ModeratorId = 0
+	if filter.YearMax != nil {
+		args = append(args, *filter.YearMax)
+		houseConditions += fmt.Sprintf(` AND year <= $%d`, len(args))
+	}
+
+	if filter.Developer != `` {
+		args = append(args, filter.Developer)
+		houseConditions += fmt.Sprintf(` AND developer = $%d`, len(args))
+	}
+
+	// The trigram index on house.address serves ILIKE with a leading wildcard.
+	if filter.Address != `` {
+		args = append(args, `%`+likeEscaper.Replace(filter.Address)+`%`)
+		houseConditions += fmt.Sprintf(` AND address ILIKE $%d`, len(args))
+	}
+
+	order := `id DESC`
+
+	switch filter.Sort {
+	case models.FlatSortPriceAsc:
+		order = `price, id`
+	case models.FlatSortPriceDesc:
+		order = `price DESC, id DESC`
+	}
+
+	if filter.After != nil {
+		switch filter.Sort {
+		case models.FlatSortPriceAsc:
+			args = append(args, filter.After.Price, filter.After.Id)
+			conditions += fmt.Sprintf(` AND (price, id) > ($%d, $%d)`, len(args)-1, len(args))
+		case models.FlatSortPriceDesc:
+			args = append(args, filter.After.Price, filter.After.Id)
+			conditions += fmt.Sprintf(` AND (price, id) < ($%d, $%d)`, len(args)-1, len(args))
+		default:
+			args = append(args, filter.After.Id)
+			conditions += fmt.Sprintf(` AND id < $%d`, len(args))
 		}
+	}
 
-		flats = append(flats, currFlat)
+	// One extra row tells whether there is a next page.
+	args = append(args, filter.Limit+1)
+	query := `SELECT ` + flatColumns + ` FROM flat
+	WHERE ` + conditions + ` AND house_id IN (SELECT id FROM house WHERE ` + houseConditions + `)
+	ORDER BY ` + order + fmt.Sprintf(` LIMIT $%d`, len(args))
+
+	flats, err := storage.queryFlats(ctx, query, args...)
+	if err != nil {
+		return page, err
+	}
+
+	if len(flats) > filter.Limit {
+		flats = flats[:filter.Limit]
+		last := flats[len(flats)-1]
+		page.NextCursor = models.FlatSearchCursor{Sort: filter.Sort, Price: last.Price, Id: last.Id}.Encode()
+	}
+
+	if flats != nil {
+		page.Flats = flats
 	}
 
-	return flats, nil
+	return page, nil
 }
 
-func (storage *Storage) CreateFlat(flat models.Flat) (models.Flat, error) {
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// CreateFlat adds a flat owned by flat.OwnerId in the created status.
+func (storage *Storage) CreateFlat(ctx context.Context, flat models.Flat) (models.Flat, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
 	flat.Status = `created`
 
-	query := `INSERT INTO flat (house_id, price, rooms, flat_num, status, moderator_id) 
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return flat, err
+	}
+
+	defer tx.Rollback()
+
+	flat.ModeratorId = ``
+
+	query := `INSERT INTO flat (house_id, price, rooms, flat_num, status, owner_id) 
 	VALUES($1, $2, $3, $4, $5, $6) RETURNING id`
 
-	if err := storage.Db.QueryRow(query, flat.HouseId, flat.Price, flat.Rooms, flat.Num, flat.Status, flat.ModeratorId).Scan(&flat.Id); err != nil {
+	err = tx.QueryRowContext(ctx, query, flat.HouseId, flat.Price, flat.Rooms, flat.Num, flat.Status, flat.OwnerId).Scan(&flat.Id)
+
+	if isUniqueViolation(err, `unique_house_flat`) {
+		return flat, store.ErrFlatNumberTaken
+	}
+
+	if isForeignKeyViolation(err, `flat_house_id_fkey`) {
+		return flat, store.ErrHouseNotFound
+	}
+
+	if err != nil {
 		return flat, err
 	}
 
-	return flat, nil
+	if err := updateAtHouseLastFlatTime(ctx, tx, flat.HouseId); err != nil {
+		return flat, err
+	}
+
+	if err := insertStatusHistory(ctx, tx, models.FlatStatusChange{FlatId: flat.Id, ActorId: flat.OwnerId, NewStatus: flat.Status}); err != nil {
+		return flat, err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.OutboxEventFlatCreated, models.FlatEvent{Flat: flat}); err != nil {
+		return flat, err
+	}
+
+	return flat, tx.Commit()
+}
+
+func updateAtHouseLastFlatTime(ctx context.Context, tx *sql.Tx, houseId int64) error {
+	currTime := time.Now().UTC().Format(timestampLayout)
+	query := `UPDATE house SET update_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, currTime, houseId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return store.ErrHouseDeleted
+	}
+
+	return nil
 }
 
-func (storage *Storage) UpdateAtHouseLastFlatTime(houseId int64) error {
-	currTime := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
-	query := `UPDATE house SET update_at = $1 WHERE id = $2`
-	_, err := storage.Db.Exec(query, currTime, houseId)
+// insertOutboxEvent stores the event in the same transaction as the change
+// it describes, so the outbox worker sees it if and only if the change is committed.
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, eventType string, payload any) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO outbox (event_type, payload) VALUES($1, $2)`
+	_, err = tx.ExecContext(ctx, query, eventType, jsonPayload)
 
 	return err
 }
 
-func (storage *Storage) CreateHouse(house models.House) (models.House, error) {
-	house.CreatedAt = time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+func (storage *Storage) CreateHouse(ctx context.Context, house models.House) (models.House, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	house.CreatedAt = time.Now().UTC().Format(timestampLayout)
 	query := `INSERT INTO house (address, year, developer, created_at) 
 		VALUES($1, $2, $3, $4) RETURNING id`
 
-	if err := storage.Db.QueryRow(query, house.Address, house.Year, house.Developer, house.CreatedAt).Scan(&house.Id); err != nil {
+	if err := storage.Db.QueryRowContext(ctx, query, house.Address, house.Year, house.Developer, house.CreatedAt).Scan(&house.Id); err != nil {
 		return house, err
 	}
 
 	return house, nil
 }
 
-func (storage *Storage) UpdateFlat(flat models.Flat) (models.Flat, error) {
-	var currStatus string
-	var currModeratorId *int
+// DeleteFlat archives the flat. The row stays for audit and can be restored.
+func (storage *Storage) DeleteFlat(ctx context.Context, flatId int64) (models.Flat, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE flat SET deleted_at = now(), moderator_id = NULL, moderation_expires_at = NULL
+	WHERE id = $1 AND deleted_at IS NULL RETURNING ` + flatColumns
+	flat, err := scanFlat(storage.Db.QueryRowContext(ctx, query, flatId))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return flat, store.ErrNotFound
+	}
+
+	return flat, err
+}
+
+// RestoreFlat brings an archived flat back. A flat of an archived house can
+// only come back together with the house.
+func (storage *Storage) RestoreFlat(ctx context.Context, flatId int64) (models.Flat, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
 
-	query := `SELECT status, moderator_id FROM flat WHERE id = $1`
-	err := storage.Db.QueryRow(query, flat.Id).Scan(&currStatus, &currModeratorId)
+	var houseDeleted bool
+
+	tx, err := storage.Db.BeginTx(ctx, nil)
 	if err != nil {
-		return flat, err
+		return models.Flat{}, err
 	}
 
-	if currStatus == `on moderation` && (currModeratorId == nil || *currModeratorId != flat.ModeratorId) {
-		return models.Flat{Id: -1}, err
+	defer tx.Rollback()
+
+	query := `SELECT h.deleted_at IS NOT NULL FROM flat f JOIN house h ON h.id = f.house_id
+	WHERE f.id = $1 AND f.deleted_at IS NOT NULL FOR UPDATE OF f`
+	err = tx.QueryRowContext(ctx, query, flatId).Scan(&houseDeleted)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Flat{}, store.ErrNotFound
 	}
 
-	if flat.Status == `on moderation` {
-		query = `UPDATE flat SET status = $1, moderator_id = $2 WHERE id = $3 RETURNING price, rooms, house_id, flat_num`
-		err = storage.Db.QueryRow(query, flat.Status, flat.ModeratorId, flat.Id).Scan(&flat.Price, &flat.Rooms, &flat.HouseId, &flat.Num)
-	} else {
-		query = `UPDATE flat SET status = $1 WHERE id = $2 RETURNING price, rooms, house_id, flat_num, moderator_id`
-		err = storage.Db.QueryRow(query, flat.Status, flat.Id).Scan(&flat.Price, &flat.Rooms, &flat.HouseId, &flat.Num, &currModeratorId)
-		if currModeratorId != nil {
-			flat.ModeratorId = *currModeratorId
-		} else {
-			flat.ModeratorId = 0
-		}
+	if err != nil {
+		return models.Flat{}, err
 	}
 
+	if houseDeleted {
+		return models.Flat{}, store.ErrHouseDeleted
+	}
+
+	flat, err := scanFlat(tx.QueryRowContext(ctx, `UPDATE flat SET deleted_at = NULL WHERE id = $1 RETURNING `+flatColumns, flatId))
 	if err != nil {
 		return flat, err
 	}
 
-	return flat, nil
+	return flat, tx.Commit()
 }
 
-func (storage *Storage) CreateUser(user models.User) (models.User, error) {
-	query := `INSERT INTO users (email, password_hash, user_type) 
-		VALUES($1, $2, $3) RETURNING id`
-	err := storage.Db.QueryRow(query, user.Email, user.Password, user.UserType).Scan(&user.Id)
+// DeleteHouse archives the house together with its flats. The flats share
+// the deleted_at of the house, which is how RestoreHouse finds them.
+func (storage *Storage) DeleteHouse(ctx context.Context, houseId int64) (models.House, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
 
-	return user, err
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.House{}, err
+	}
+
+	defer tx.Rollback()
+
+	query := `UPDATE house SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL RETURNING ` + houseColumns
+	house, err := scanHouse(tx.QueryRowContext(ctx, query, houseId))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return house, store.ErrNotFound
+	}
+
+	if err != nil {
+		return house, err
+	}
+
+	query = `UPDATE flat SET deleted_at = $1, moderator_id = NULL, moderation_expires_at = NULL
+	WHERE house_id = $2 AND deleted_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, house.DeletedAt, houseId); err != nil {
+		return house, err
+	}
+
+	return house, tx.Commit()
 }
 
-func (storage *Storage) GetUserById(id string) (models.User, error) {
-	query := `SELECT password_hash, user_type, email FROM users WHERE id = $1`
-	user := models.User{Id: id}
-	err := storage.Db.QueryRow(query, id).Scan(&user.Password, &user.UserType, &user.Email)
+// RestoreHouse brings back the house and the flats archived with it. Flats
+// archived on their own before stay archived.
+func (storage *Storage) RestoreHouse(ctx context.Context, houseId int64) (models.House, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
 
-	return user, err
+	var deletedAt time.Time
+
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.House{}, err
+	}
+
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `SELECT deleted_at FROM house WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`, houseId).Scan(&deletedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.House{}, store.ErrNotFound
+	}
+
+	if err != nil {
+		return models.House{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE flat SET deleted_at = NULL WHERE house_id = $1 AND deleted_at = $2`, houseId, deletedAt); err != nil {
+		return models.House{}, err
+	}
+
+	house, err := scanHouse(tx.QueryRowContext(ctx, `UPDATE house SET deleted_at = NULL WHERE id = $1 RETURNING `+houseColumns, houseId))
+	if err != nil {
+		return house, err
+	}
+
+	return house, tx.Commit()
+}
+
+const houseColumns = `id, address, year, COALESCE(developer, ''), COALESCE(created_at, ''), COALESCE(update_at, ''), deleted_at`
+
+func scanHouse(row rowScanner) (models.House, error) {
+	var house models.House
+
+	err := row.Scan(&house.Id, &house.Address, &house.Year, &house.Developer, &house.CreatedAt, &house.UpdateAt, &house.DeletedAt)
+
+	return house, err
+}
+
+// GetHouseById returns the house, archived ones included.
+func (storage *Storage) GetHouseById(ctx context.Context, houseId int64) (models.House, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	house, err := scanHouse(storage.Db.QueryRowContext(ctx, `SELECT `+houseColumns+` FROM house WHERE id = $1`, houseId))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return house, store.ErrNotFound
+	}
+
+	return house, err
+}
+
+// UpdateHouse corrects the house metadata. Archived houses can not be changed.
+func (storage *Storage) UpdateHouse(ctx context.Context, houseId int64, edit models.HouseEdit) (models.House, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE house SET address = COALESCE($1, address), year = COALESCE($2, year), developer = COALESCE($3, developer)
+	WHERE id = $4 AND deleted_at IS NULL RETURNING ` + houseColumns
+	house, err := scanHouse(storage.Db.QueryRowContext(ctx, query, edit.Address, edit.Year, edit.Developer, houseId))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return house, store.ErrNotFound
+	}
+
+	return house, err
+}
+
+// GetHouses lists houses that are not archived, ordered by id.
+func (storage *Storage) GetHouses(ctx context.Context, filter models.HouseFilter) (models.HousePage, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	page := models.HousePage{Houses: []models.House{}}
+	conditions := `deleted_at IS NULL`
+	var args []any
+
+	if filter.Developer != `` {
+		args = append(args, filter.Developer)
+		conditions += fmt.Sprintf(` AND developer = $%d`, len(args))
+	}
+
+	if filter.YearMin != nil {
+		args = append(args, *filter.YearMin)
+		conditions += fmt.Sprintf(` AND year >= $%d`, len(args))
+	}
+
+	if filter.YearMax != nil {
+		args = append(args, *filter.YearMax)
+		conditions += fmt.Sprintf(` AND year <= $%d`, len(args))
+	}
+
+	// update_at is written with timestampLayout, so its values compare as strings.
+	if filter.UpdatedSince != nil {
+		args = append(args, filter.UpdatedSince.UTC().Format(timestampLayout))
+		conditions += fmt.Sprintf(` AND update_at > $%d`, len(args))
+	}
+
+	if filter.After != nil {
+		args = append(args, filter.After.Id)
+		conditions += fmt.Sprintf(` AND id > $%d`, len(args))
+	}
+
+	// One extra row tells whether there is a next page.
+	args = append(args, filter.Limit+1)
+	query := `SELECT ` + houseColumns + ` FROM house WHERE ` + conditions + fmt.Sprintf(` ORDER BY id LIMIT $%d`, len(args))
+
+	rows, err := storage.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		if len(page.Houses) == filter.Limit {
+			page.NextCursor = models.HouseCursor{Id: page.Houses[len(page.Houses)-1].Id}.Encode()
+			break
+		}
+
+		house, err := scanHouse(rows)
+		if err != nil {
+			return page, err
+		}
+
+		page.Houses = append(page.Houses, house)
+	}
+
+	return page, rows.Err()
+}
+
+// UpdateFlat changes the flat status on behalf of flat.ModeratorId. The row is
+// locked for the whole check, so two moderators can not both pass the
+// transition check for the same flat. Declining requires a reason code from
+// the decline_reason list.
+func (storage *Storage) UpdateFlat(ctx context.Context, flat models.Flat) (models.Flat, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	var currStatus string
+	var currModeratorId *string
+	var currExpiresAt *time.Time
+
+	if !models.IsValidStatus(flat.Status) {
+		return flat, store.ErrInvalidStatus
+	}
+
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return flat, err
+	}
+
+	defer tx.Rollback()
+
+	query := `SELECT status, moderator_id, moderation_expires_at FROM flat WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, flat.Id).Scan(&currStatus, &currModeratorId, &currExpiresAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return flat, store.ErrNotFound
+	}
+
+	if err != nil {
+		return flat, err
+	}
+
+	// Asking for "on moderation" again renews the lease for its holder and
+	// takes the flat over for anyone else once the lease has lapsed.
+	renew := currStatus == models.StatusOnModeration && flat.Status == models.StatusOnModeration
+
+	if currStatus == models.StatusOnModeration && (currModeratorId == nil || *currModeratorId != flat.ModeratorId) {
+		leaseLapsed := currExpiresAt == nil || !time.Now().Before(*currExpiresAt)
+		if !renew || !leaseLapsed {
+			return flat, store.ErrFlatLocked
+		}
+	}
+
+	if !renew && !storage.Transitions.Allowed(currStatus, flat.Status) {
+		return flat, fmt.Errorf("%w: from %q to %q", store.ErrInvalidStatusTransition, currStatus, flat.Status)
+	}
+
+	if flat.Status == models.StatusDeclined {
+		if err := checkDeclineReason(ctx, tx, flat.DeclineReasonCode); err != nil {
+			return flat, err
+		}
+	}
+
+	flat, err = storage.setFlatStatus(ctx, tx, flat, models.FlatStatusChange{ActorId: flat.ModeratorId, OldStatus: currStatus})
+	if err != nil {
+		return flat, err
+	}
+
+	return flat, tx.Commit()
+}
+
+// setFlatStatus writes the new status together with the moderation lock:
+// taking a flat for moderation (re)starts the lease, releasing it to created
+// drops the moderator, any other status ends the lease. The decline reason is
+// kept only while the flat is declined. A real change of status is recorded
+// in the status history with the actor and old status from change; renewing
+// the lease keeps the status and records nothing.
+func (storage *Storage) setFlatStatus(ctx context.Context, tx *sql.Tx, flat models.Flat, change models.FlatStatusChange) (models.Flat, error) {
+	var query string
+	var args []any
+
+	if flat.Status != models.StatusDeclined {
+		flat.DeclineReasonCode = ``
+		flat.DeclineReason = ``
+	}
+
+	switch flat.Status {
+	case models.StatusOnModeration:
+		query = `UPDATE flat SET status = $1, moderator_id = $2, moderation_expires_at = now() + $3 * interval '1 millisecond',
+		decline_reason_code = NULL, decline_reason = NULL WHERE id = $4`
+		args = []any{flat.Status, flat.ModeratorId, storage.ModerationLease.Milliseconds(), flat.Id}
+	case models.StatusCreated:
+		query = `UPDATE flat SET status = $1, moderator_id = NULL, moderation_expires_at = NULL,
+		decline_reason_code = NULL, decline_reason = NULL WHERE id = $2`
+		args = []any{flat.Status, flat.Id}
+	case models.StatusDeclined:
+		query = `UPDATE flat SET status = $1, moderation_expires_at = NULL,
+		decline_reason_code = $2, decline_reason = $3 WHERE id = $4`
+		args = []any{flat.Status, flat.DeclineReasonCode, flat.DeclineReason, flat.Id}
+	default:
+		query = `UPDATE flat SET status = $1, moderation_expires_at = NULL,
+		decline_reason_code = NULL, decline_reason = NULL WHERE id = $2`
+		args = []any{flat.Status, flat.Id}
+	}
+
+	flat, err := scanFlat(tx.QueryRowContext(ctx, query+` RETURNING `+flatColumns, args...))
+	if err != nil {
+		return flat, err
+	}
+
+	if change.OldStatus == flat.Status {
+		return flat, nil
+	}
+
+	change.FlatId = flat.Id
+	change.NewStatus = flat.Status
+	change.ReasonCode = flat.DeclineReasonCode
+	change.Reason = flat.DeclineReason
+
+	if err := insertStatusHistory(ctx, tx, change); err != nil {
+		return flat, err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.OutboxEventFlatUpdated, models.FlatEvent{Flat: flat, OldStatus: change.OldStatus}); err != nil {
+		return flat, err
+	}
+
+	return flat, nil
+}
+
+func checkDeclineReason(ctx context.Context, tx *sql.Tx, code string) error {
+	var exists bool
+
+	query := `SELECT EXISTS (SELECT 1 FROM decline_reason WHERE code = $1 AND active)`
+	if err := tx.QueryRowContext(ctx, query, code).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("%w: %q", store.ErrInvalidDeclineReason, code)
+	}
+
+	return nil
+}
+
+func insertStatusHistory(ctx context.Context, tx *sql.Tx, change models.FlatStatusChange) error {
+	query := `INSERT INTO flat_status_history (flat_id, actor_id, old_status, new_status, reason_code, reason)
+	VALUES($1, $2, $3, $4, $5, $6)`
+	_, err := tx.ExecContext(ctx, query, change.FlatId, change.ActorId, nullString(change.OldStatus), change.NewStatus, nullString(change.ReasonCode), nullString(change.Reason))
+
+	return err
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ``}
+}
+
+// GetFlatStatusHistory returns the status changes of the flat, oldest first.
+func (storage *Storage) GetFlatStatusHistory(ctx context.Context, flatId int64) ([]models.FlatStatusChange, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	var exists bool
+
+	if err := storage.Db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM flat WHERE id = $1)`, flatId).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, store.ErrNotFound
+	}
+
+	query := `SELECT id, flat_id, actor_id, old_status, new_status, reason_code, reason, created_at
+	FROM flat_status_history WHERE flat_id = $1 ORDER BY created_at, id`
+
+	rows, err := storage.Db.QueryContext(ctx, query, flatId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	history := []models.FlatStatusChange{}
+
+	for rows.Next() {
+		var change models.FlatStatusChange
+		var actorId, oldStatus, reasonCode, reason sql.NullString
+
+		if err := rows.Scan(&change.Id, &change.FlatId, &actorId, &oldStatus, &change.NewStatus, &reasonCode, &reason, &change.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		change.ActorId = actorId.String
+		change.OldStatus = oldStatus.String
+		change.ReasonCode = reasonCode.String
+		change.Reason = reason.String
+
+		history = append(history, change)
+	}
+
+	return history, rows.Err()
+}
+
+// ResubmitFlat applies the owner's edits to a declined flat and sends it back
+// to the moderation queue.
+func (storage *Storage) ResubmitFlat(ctx context.Context, flatId int64, ownerId string, edit models.FlatEdit) (models.Flat, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	flat := models.Flat{Id: flatId, Status: models.StatusCreated}
+
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return flat, err
+	}
+
+	defer tx.Rollback()
+
+	currStatus, err := lockOwnedFlat(ctx, tx, flatId, ownerId)
+	if err != nil {
+		return flat, err
+	}
+
+	if currStatus != models.StatusDeclined {
+		return flat, fmt.Errorf("%w: from %q to %q", store.ErrInvalidStatusTransition, currStatus, models.StatusCreated)
+	}
+
+	query := `UPDATE flat SET price = COALESCE($1, price), rooms = COALESCE($2, rooms) WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, query, edit.Price, edit.Rooms, flatId); err != nil {
+		return flat, err
+	}
+
+	flat, err = storage.setFlatStatus(ctx, tx, flat, models.FlatStatusChange{ActorId: ownerId, OldStatus: currStatus})
+	if err != nil {
+		return flat, err
+	}
+
+	return flat, tx.Commit()
+}
+
+// lockOwnedFlat locks the flat for the rest of the transaction and returns its
+// status, provided the flat belongs to ownerId.
+func lockOwnedFlat(ctx context.Context, tx *sql.Tx, flatId int64, ownerId string) (string, error) {
+	var status string
+	var owner *string
+
+	err := tx.QueryRowContext(ctx, `SELECT status, owner_id FROM flat WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, flatId).Scan(&status, &owner)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return status, store.ErrNotFound
+	}
+
+	if err != nil {
+		return status, err
+	}
+
+	if owner == nil || *owner != ownerId {
+		return status, store.ErrNotFlatOwner
+	}
+
+	return status, nil
+}
+
+// EditFlat changes the price, rooms and number of a flat on behalf of its
+// owner. An approved flat goes back to the moderation queue. A flat under an
+// active moderation lease can not be edited, the moderator would otherwise
+// decide on values they have not seen.
+func (storage *Storage) EditFlat(ctx context.Context, flatId int64, ownerId string, edit models.FlatEdit) (models.Flat, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	var flat models.Flat
+
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return flat, err
+	}
+
+	defer tx.Rollback()
+
+	currStatus, err := lockOwnedFlat(ctx, tx, flatId, ownerId)
+	if err != nil {
+		return flat, err
+	}
+
+	if currStatus == models.StatusOnModeration {
+		var leaseActive bool
+
+		query := `SELECT COALESCE(moderation_expires_at > now(), false) FROM flat WHERE id = $1`
+		if err := tx.QueryRowContext(ctx, query, flatId).Scan(&leaseActive); err != nil {
+			return flat, err
+		}
+
+		if leaseActive {
+			return flat, store.ErrFlatLocked
+		}
+	}
+
+	query := `UPDATE flat SET price = COALESCE($1, price), rooms = COALESCE($2, rooms), flat_num = COALESCE($3, flat_num)
+	WHERE id = $4 RETURNING ` + flatColumns
+	flat, err = scanFlat(tx.QueryRowContext(ctx, query, edit.Price, edit.Rooms, edit.Num, flatId))
+
+	if isUniqueViolation(err, `unique_house_flat`) {
+		return flat, store.ErrFlatNumberTaken
+	}
+
+	if err != nil {
+		return flat, err
+	}
+
+	if currStatus != models.StatusApproved {
+		if err := insertOutboxEvent(ctx, tx, models.OutboxEventFlatUpdated, models.FlatEvent{Flat: flat, OldStatus: currStatus}); err != nil {
+			return flat, err
+		}
+
+		return flat, tx.Commit()
+	}
+
+	flat.Status = models.StatusCreated
+
+	flat, err = storage.setFlatStatus(ctx, tx, flat, models.FlatStatusChange{ActorId: ownerId, OldStatus: currStatus})
+	if err != nil {
+		return flat, err
+	}
+
+	return flat, tx.Commit()
+}
+
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == `23505` && pqErr.Constraint == constraint
+}
+
+func isForeignKeyViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == `23503` && pqErr.Constraint == constraint
+}
+
+func (storage *Storage) GetDeclineReasons(ctx context.Context) ([]models.DeclineReason, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := storage.Db.QueryContext(ctx, `SELECT code, description FROM decline_reason WHERE active ORDER BY code`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	reasons := []models.DeclineReason{}
+
+	for rows.Next() {
+		var reason models.DeclineReason
+		if err := rows.Scan(&reason.Code, &reason.Description); err != nil {
+			return nil, err
+		}
+
+		reasons = append(reasons, reason)
+	}
+
+	return reasons, rows.Err()
+}
+
+// SaveDeclineReason adds a reason to the list or updates the description of
+// an existing one, bringing it back if it was removed.
+func (storage *Storage) SaveDeclineReason(ctx context.Context, reason models.DeclineReason) (models.DeclineReason, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO decline_reason (code, description) VALUES($1, $2)
+	ON CONFLICT (code) DO UPDATE SET description = EXCLUDED.description, active = true`
+
+	_, err := storage.Db.ExecContext(ctx, query, reason.Code, reason.Description)
+
+	return reason, err
+}
+
+// DeactivateDeclineReason removes the reason from the list. Flats and history
+// records that already use it keep the code.
+func (storage *Storage) DeactivateDeclineReason(ctx context.Context, code string) error {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	result, err := storage.Db.ExecContext(ctx, `UPDATE decline_reason SET active = false WHERE code = $1 AND active`, code)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetModerationQueue lists the flats awaiting moderation across all houses,
+// oldest first: created flats and flats whose moderation lease has lapsed.
+func (storage *Storage) GetModerationQueue(ctx context.Context, filter models.ModerationQueueFilter) (models.ModerationQueuePage, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	page := models.ModerationQueuePage{Flats: []models.Flat{}}
+
+	conditions, args := moderationQueueConditions(filter)
+
+	if filter.After != nil {
+		args = append(args, filter.After.CreatedAt, filter.After.Id)
+		conditions += fmt.Sprintf(` AND (f.created_at, f.id) > ($%d, $%d)`, len(args)-1, len(args))
+	}
+
+	// One extra row tells whether there is a next page.
+	args = append(args, filter.Limit+1)
+	query := `SELECT f.id, f.house_id, f.price, f.rooms, f.status, f.flat_num, f.created_at
+	FROM flat f JOIN house h ON h.id = f.house_id
+	WHERE ` + conditions + fmt.Sprintf(` ORDER BY f.created_at, f.id LIMIT $%d`, len(args))
+
+	rows, err := storage.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, err
+	}
+
+	defer rows.Close()
+
+	var last models.FlatCursor
+
+	for rows.Next() {
+		if len(page.Flats) == filter.Limit {
+			page.NextCursor = last.Encode()
+			break
+		}
+
+		var flat models.Flat
+		if err := rows.Scan(&flat.Id, &flat.HouseId, &flat.Price, &flat.Rooms, &flat.Status, &flat.Num, &last.CreatedAt); err != nil {
+			return page, err
+		}
+
+		last.Id = flat.Id
+		page.Flats = append(page.Flats, flat)
+	}
+
+	return page, rows.Err()
+}
+
+// TakeNextFlatForModeration moves the oldest flat awaiting moderation that
+// matches the filter to the moderator. Flats locked by concurrent calls are skipped, so two
+// moderators never get the same flat.
+func (storage *Storage) TakeNextFlatForModeration(ctx context.Context, moderatorId string, filter models.ModerationQueueFilter) (models.Flat, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	flat := models.Flat{Status: models.StatusOnModeration, ModeratorId: moderatorId}
+
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return flat, err
+	}
+
+	defer tx.Rollback()
+
+	conditions, args := moderationQueueConditions(filter)
+	query := `SELECT f.id, f.status FROM flat f JOIN house h ON h.id = f.house_id
+	WHERE ` + conditions + ` ORDER BY f.created_at, f.id LIMIT 1 FOR UPDATE OF f SKIP LOCKED`
+
+	var currStatus string
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&flat.Id, &currStatus)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return flat, store.ErrNotFound
+	}
+
+	if err != nil {
+		return flat, err
+	}
+
+	flat, err = storage.setFlatStatus(ctx, tx, flat, models.FlatStatusChange{ActorId: moderatorId, OldStatus: currStatus})
+	if err != nil {
+		return flat, err
+	}
+
+	return flat, tx.Commit()
+}
+
+// moderationQueueConditions matches the flats awaiting moderation. A flat
+// whose lease has lapsed is back in the queue, like in UpdateFlat a flat
+// without an expiry counts as lapsed.
+func moderationQueueConditions(filter models.ModerationQueueFilter) (string, []any) {
+	conditions := `(f.status = 'created' OR (f.status = 'on moderation' AND (f.moderation_expires_at IS NULL OR f.moderation_expires_at < now())))
+	AND f.deleted_at IS NULL AND h.deleted_at IS NULL`
+	var args []any
+
+	if filter.HouseId != 0 {
+		args = append(args, filter.HouseId)
+		conditions += fmt.Sprintf(` AND f.house_id = $%d`, len(args))
+	}
+
+	if filter.Developer != `` {
+		args = append(args, filter.Developer)
+		conditions += fmt.Sprintf(` AND h.developer = $%d`, len(args))
+	}
+
+	if filter.PriceMin != nil {
+		args = append(args, *filter.PriceMin)
+		conditions += fmt.Sprintf(` AND f.price >= $%d`, len(args))
+	}
+
+	if filter.PriceMax != nil {
+		args = append(args, *filter.PriceMax)
+		conditions += fmt.Sprintf(` AND f.price <= $%d`, len(args))
+	}
+
+	return conditions, args
+}
+
+func (storage *Storage) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO users (email, password_hash, user_type) 
+		VALUES($1, $2, $3) RETURNING id`
+	err := storage.Db.QueryRowContext(ctx, query, user.Email, user.Password, user.UserType).Scan(&user.Id)
+
+	if isUniqueViolation(err, `users_email_key`) {
+		return user, store.ErrEmailTaken
+	}
+
+	return user, err
+}
+
+func (storage *Storage) GetUserById(ctx context.Context, id string) (models.User, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT password_hash, user_type, email FROM users WHERE id = $1`
+	user := models.User{Id: id}
+	err := storage.Db.QueryRowContext(ctx, query, id).Scan(&user.Password, &user.UserType, &user.Email)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, store.ErrNotFound
+	}
+
+	return user, err
+}
+
+func (storage *Storage) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, password_hash, user_type FROM users WHERE email = $1`
+	user := models.User{Email: email}
+	err := storage.Db.QueryRowContext(ctx, query, email).Scan(&user.Id, &user.Password, &user.UserType)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, store.ErrNotFound
+	}
+
+	return user, err
+}
+
+// SetUserPassword replaces the password hash of a user and revokes the
+// refresh tokens issued with the old password.
+func (storage *Storage) SetUserPassword(ctx context.Context, email string, passwordHash string) (models.User, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	user := models.User{Email: email}
+
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return user, err
+	}
+
+	defer tx.Rollback()
+
+	query := `UPDATE users SET password_hash = $2 WHERE email = $1 RETURNING id, user_type`
+	err = tx.QueryRowContext(ctx, query, email, passwordHash).Scan(&user.Id, &user.UserType)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, store.ErrNotFound
+	}
+
+	if err != nil {
+		return user, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE refresh_token SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, user.Id); err != nil {
+		return user, err
+	}
+
+	return user, tx.Commit()
+}
+
+// SetUserType makes a user a client or a moderator. Access tokens already
+// issued keep the old type until they expire.
+func (storage *Storage) SetUserType(ctx context.Context, email string, userType string) (models.User, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	user := models.User{Email: email, UserType: userType}
+
+	query := `UPDATE users SET user_type = $2 WHERE email = $1 RETURNING id`
+	err := storage.Db.QueryRowContext(ctx, query, email, userType).Scan(&user.Id)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, store.ErrNotFound
+	}
+
+	return user, err
+}
+
+func (storage *Storage) CreateRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO refresh_token (token_hash, user_id, family_id, expires_at)
+		VALUES($1, $2, gen_random_uuid(), $3)`
+	_, err := storage.Db.ExecContext(ctx, query, token.Hash, token.UserId, token.ExpiresAt)
+
+	return err
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one of the same family.
+// Presenting a token that was already rotated means it has leaked, so the whole
+// family is revoked and ErrRefreshTokenReused is returned.
+func (storage *Storage) RotateRefreshToken(ctx context.Context, oldHash string, newToken models.RefreshToken) (models.RefreshToken, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	var oldToken models.RefreshToken
+	var revokedAt *time.Time
+
+	tx, err := storage.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return oldToken, err
+	}
+
+	defer tx.Rollback()
+
+	query := `SELECT token_hash, user_id, family_id, expires_at, revoked_at FROM refresh_token WHERE token_hash = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, oldHash).Scan(&oldToken.Hash, &oldToken.UserId, &oldToken.FamilyId, &oldToken.ExpiresAt, &revokedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return oldToken, store.ErrNotFound
+	}
+
+	if err != nil {
+		return oldToken, err
+	}
+
+	if revokedAt != nil {
+		if err := revokeRefreshTokenFamily(ctx, tx, oldToken.FamilyId); err != nil {
+			return oldToken, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return oldToken, err
+		}
+
+		return oldToken, store.ErrRefreshTokenReused
+	}
+
+	if oldToken.ExpiresAt.Before(time.Now()) {
+		return oldToken, store.ErrNotFound
+	}
+
+	query = `UPDATE refresh_token SET revoked_at = now(), replaced_by = $1 WHERE token_hash = $2`
+	if _, err := tx.ExecContext(ctx, query, newToken.Hash, oldHash); err != nil {
+		return oldToken, err
+	}
+
+	query = `INSERT INTO refresh_token (token_hash, user_id, family_id, expires_at) VALUES($1, $2, $3, $4)`
+	if _, err := tx.ExecContext(ctx, query, newToken.Hash, oldToken.UserId, oldToken.FamilyId, newToken.ExpiresAt); err != nil {
+		return oldToken, err
+	}
+
+	return oldToken, tx.Commit()
+}
+
+// RevokeRefreshToken revokes the token together with every token rotated from it.
+// Unknown tokens and tokens of other users are ignored.
+func (storage *Storage) RevokeRefreshToken(ctx context.Context, hash string, userId string) error {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE refresh_token SET revoked_at = now()
+		WHERE revoked_at IS NULL AND family_id = (SELECT family_id FROM refresh_token WHERE token_hash = $1 AND user_id = $2)`
+	_, err := storage.Db.ExecContext(ctx, query, hash, userId)
+
+	return err
+}
+
+func revokeRefreshTokenFamily(ctx context.Context, tx *sql.Tx, familyId string) error {
+	query := `UPDATE refresh_token SET revoked_at = now() WHERE family_id = $1 AND revoked_at IS NULL`
+	_, err := tx.ExecContext(ctx, query, familyId)
+
+	return err
+}
+
+func (storage *Storage) CreateSubscription(ctx context.Context, subscription models.Subscription) (models.Subscription, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	subscription.CreatedAt = time.Now().UTC().Format(timestampLayout)
+	// Nothing is inserted for a missing or archived house.
+	query := `INSERT INTO subscription (house_id, email, created_at)
+		SELECT id, $2, $3 FROM house WHERE id = $1 AND deleted_at IS NULL
+		ON CONFLICT ON CONSTRAINT unique_house_subscription DO UPDATE SET email = EXCLUDED.email
+		RETURNING id, created_at`
+
+	err := storage.Db.QueryRowContext(ctx, query, subscription.HouseId, subscription.Email, subscription.CreatedAt).Scan(&subscription.Id, &subscription.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return subscription, store.ErrNotFound
+	}
+
+	return subscription, err
+}
+
+func (storage *Storage) GetSubscriptionsByHouseID(ctx context.Context, houseId int64) ([]models.Subscription, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, house_id, email, created_at FROM subscription WHERE house_id = $1`
+
+	rows, err := storage.Db.QueryContext(ctx, query, houseId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var subscriptions []models.Subscription
+
+	for rows.Next() {
+		var currSubscription models.Subscription
+		if err := rows.Scan(&currSubscription.Id, &currSubscription.HouseId, &currSubscription.Email, &currSubscription.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, currSubscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// ClaimOutboxEvents takes up to limit pending events and hides them from other
+// workers for the lease duration. An event that is neither completed nor failed
+// before the lease expires is handed out again, which gives at-least-once delivery.
+func (storage *Storage) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE outbox SET attempts = attempts + 1, next_attempt_at = now() + $2 * interval '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE processed_at IS NULL AND next_attempt_at <= now()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, payload, attempts`
+
+	rows, err := storage.Db.QueryContext(ctx, query, limit, lease.Milliseconds())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+
+	for rows.Next() {
+		var currEvent models.OutboxEvent
+		if err := rows.Scan(&currEvent.Id, &currEvent.EventType, &currEvent.Payload, &currEvent.Attempts); err != nil {
+			return nil, err
+		}
+
+		events = append(events, currEvent)
+	}
+
+	return events, rows.Err()
+}
+
+func (storage *Storage) CompleteOutboxEvent(ctx context.Context, id int64) error {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE outbox SET processed_at = now(), last_error = NULL WHERE id = $1`
+	_, err := storage.Db.ExecContext(ctx, query, id)
+
+	return err
+}
+
+func (storage *Storage) FailOutboxEvent(ctx context.Context, id int64, nextAttemptAt time.Time, reason string) error {
+	ctx, cancel := storage.withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE outbox SET next_attempt_at = $1, last_error = $2 WHERE id = $3`
+	_, err := storage.Db.ExecContext(ctx, query, nextAttemptAt, reason, id)
+
+	return err
 }
@@ -1,25 +1,29 @@
 package redis
 
 import (
+	"avitoBootcamp/internal/config"
 	"avitoBootcamp/internal/models"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
 type RedisCache struct {
-	Client *redis.Client
+	Client         *redis.Client
+	flatsTTL       time.Duration
+	commandTimeout time.Duration
 }
 
-func New() (*RedisCache, error) {
+func New(cfg config.Redis) (*RedisCache, error) {
 	client := redis.NewClient(&redis.Options{
-		Addr:     "redis:6379",
-		Password: "",
-		DB:       0,
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
 	})
 
 	_, err := client.Ping(context.Background()).Result()
@@ -27,36 +31,38 @@ func New() (*RedisCache, error) {
 		return nil, err
 	}
 
-	return &RedisCache{Client: client}, nil
+	return &RedisCache{Client: client, flatsTTL: cfg.FlatsTTL, commandTimeout: cfg.CommandTimeout}, nil
 }
 
-func NewForTest() (*RedisCache, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
-	})
-
-	_, err := client.Ping(context.Background()).Result()
-	if err != nil {
-		return nil, err
+// withTimeout applies the command timeout, so a stuck Redis fails the call
+// instead of hanging the request.
+func (r *RedisCache) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.commandTimeout <= 0 {
+		return context.WithCancel(ctx)
 	}
 
-	return &RedisCache{Client: client}, nil
+	return context.WithTimeout(ctx, r.commandTimeout)
+}
+
+// flatsKeyPrefix is shared by all cached query shapes of the house flats
+// for one user type, DeleteFlatsByHouseId removes them by this prefix.
+func flatsKeyPrefix(houseId int64, userType string) string {
+	return fmt.Sprintf(`houseID:%d,userType:%s,query:`, houseId, userType)
 }
 
-func (r *RedisCache) PutFlatsByHouseID(flats []models.Flat, houseId int64, userType string) error {
-	ctx := context.Background()
+func (r *RedisCache) PutFlatsByHouseID(ctx context.Context, page models.HouseFlatsPage, houseId int64, userType string, queryKey string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
 
-	jsonFlats, err := json.Marshal(flats)
+	jsonPage, err := json.Marshal(page)
 
 	if err != nil {
 		slog.Error("Failed to marshal flats", slog.Any("err", err))
 		return err
 	}
 
-	keyRequest := fmt.Sprintf(`houseID:%d,userType:%s`, houseId, userType)
-	request := r.Client.Set(ctx, keyRequest, jsonFlats, 5*time.Minute)
+	keyRequest := flatsKeyPrefix(houseId, userType) + queryKey
+	request := r.Client.Set(ctx, keyRequest, jsonPage, r.flatsTTL)
 
 	if err := request.Err(); err != nil {
 		slog.Error("Failed to set flats in cache", slog.Any("err", err))
@@ -68,9 +74,10 @@ func (r *RedisCache) PutFlatsByHouseID(flats []models.Flat, houseId int64, userT
 	return nil
 }
 
-func (r *RedisCache) GetFlatsByHouseID(houseId int64, userType string) ([]byte, error) {
-	ctx := context.Background()
-	keyRequest := fmt.Sprintf(`houseID:%d,userType:%s`, houseId, userType)
+func (r *RedisCache) GetFlatsByHouseID(ctx context.Context, houseId int64, userType string, queryKey string) ([]byte, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	keyRequest := flatsKeyPrefix(houseId, userType) + queryKey
 	request := r.Client.Get(ctx, keyRequest)
 
 	if err := request.Err(); err != nil {
@@ -89,13 +96,135 @@ func (r *RedisCache) GetFlatsByHouseID(houseId int64, userType string) ([]byte,
 	return []byte(data), nil
 }
 
-func (r *RedisCache) DeleteFlatsByHouseId(houseId int64, userType string) {
-	ctx := context.Background()
-	key := fmt.Sprintf(`houseID:%d,userType:%s`, houseId, userType)
+// DeleteFlatsByHouseId drops every cached query shape of the house flats for
+// the user type. The keys are found with SCAN, so Redis is not blocked.
+func (r *RedisCache) DeleteFlatsByHouseId(ctx context.Context, houseId int64, userType string) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
 
-	if err := r.Client.Del(ctx, key).Err(); err != nil {
+	keys, err := r.scanKeys(ctx, flatsKeyPrefix(houseId, userType)+`*`)
+
+	if err != nil {
+		slog.Info("Error scanning keys:", slog.Any("err", err))
+		return
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	if err := r.Client.Del(ctx, keys...).Err(); err != nil {
 		slog.Info("Error deleting key:", slog.Any("err", err))
 	} else {
-		slog.Info("Key deleting successfully")
+		slog.Info("Key deleting successfully", "count", len(keys))
 	}
 }
+
+// CacheEntry is a cached page of house flats and the time it has left.
+type CacheEntry struct {
+	Key string
+	TTL time.Duration
+}
+
+// FlatsCacheEntries lists the cached pages of the house flats, of every
+// house when houseId is 0.
+func (r *RedisCache) FlatsCacheEntries(ctx context.Context, houseId int64) ([]CacheEntry, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	keys, err := r.scanKeys(ctx, flatsCachePattern(houseId))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]CacheEntry, 0, len(keys))
+
+	for _, key := range keys {
+		ttl, err := r.Client.TTL(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		// The key expired between the scan and the TTL.
+		if ttl == -2 {
+			continue
+		}
+
+		entries = append(entries, CacheEntry{Key: key, TTL: ttl})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	return entries, nil
+}
+
+// FlushFlatsCache drops the cached pages of the house flats, of every house
+// when houseId is 0, and returns how many were dropped.
+func (r *RedisCache) FlushFlatsCache(ctx context.Context, houseId int64) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	keys, err := r.scanKeys(ctx, flatsCachePattern(houseId))
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+
+	return r.Client.Del(ctx, keys...).Result()
+}
+
+func flatsCachePattern(houseId int64) string {
+	if houseId == 0 {
+		return `houseID:*`
+	}
+
+	return fmt.Sprintf(`houseID:%d,userType:*`, houseId)
+}
+
+func (r *RedisCache) scanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := r.Client.Scan(ctx, 0, pattern, 100).Iterator()
+
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+
+	return keys, iter.Err()
+}
+
+func (r *RedisCache) RevokeToken(ctx context.Context, tokenId string, ttl time.Duration) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	key := fmt.Sprintf(`revokedToken:%s`, tokenId)
+
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := r.Client.Set(ctx, key, 1, ttl).Err(); err != nil {
+		slog.Error("Failed to revoke token", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *RedisCache) IsTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	key := fmt.Sprintf(`revokedToken:%s`, tokenId)
+
+	count, err := r.Client.Exists(ctx, key).Result()
+	if err != nil {
+		slog.Error("Failed to check token revocation", slog.Any("err", err))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Ping checks that Redis answers, it backs the /readyz probe.
+func (r *RedisCache) Ping(ctx context.Context) error {
+	return r.Client.Ping(ctx).Err()
+}
@@ -0,0 +1,171 @@
+// Package validation checks requests against api.yaml before they reach the
+// handlers, so invalid input never gets to the storage.
+package validation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"avitoBootcamp/internal/apierror"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// uuidFormat accepts any hex uuid, the dummy users do not have an RFC 4122 version.
+const uuidFormat = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+
+func init() {
+	openapi3.DefineStringFormat(`uuid`, uuidFormat)
+	openapi3.DefineStringFormat(`email`, openapi3.FormatOfStringForEmail)
+}
+
+type Validator struct {
+	router routers.Router
+}
+
+func New(doc *openapi3.T) (*Validator, error) {
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// The servers of the spec would pin the routes to localhost:8080.
+	doc.Servers = nil
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Validator{router: router}, nil
+}
+
+// Middleware rejects requests that do not match their operation in the spec
+// with a 400 listing every invalid field. Requests to routes the spec does not
+// know are passed on. Authorization is left to AuthorizationMiddleware.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The API only speaks JSON, a body without a content type is read as such.
+		if r.ContentLength != 0 && r.Header.Get(`Content-Type`) == `` {
+			r.Header.Set(`Content-Type`, `application/json`)
+		}
+
+		err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:         true,
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		})
+
+		if err != nil {
+			apierror.Write(w, r, apierror.Invalid(fieldErrors(err)))
+			return
+		}
+
+		// An optional body that was left out is passed on as an empty object,
+		// the generated handlers always decode the body.
+		if route.Operation.RequestBody != nil && r.ContentLength == 0 {
+			r.Body = io.NopCloser(strings.NewReader(`{}`))
+			r.ContentLength = 2
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// fieldErrors flattens the errors of ValidateRequest. MultiError is matched by
+// type, errors.As would also find the one nested in a RequestError.
+func fieldErrors(err error) []apierror.FieldError {
+	if multiErr, ok := err.(openapi3.MultiError); ok {
+		var fields []apierror.FieldError
+		for _, err := range multiErr {
+			fields = append(fields, fieldErrors(err)...)
+		}
+
+		return fields
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return []apierror.FieldError{{Field: `request`, Message: err.Error()}}
+	}
+
+	field := `body`
+	if requestErr.Parameter != nil {
+		field = requestErr.Parameter.Name
+	}
+
+	return withField(requestErr, field)
+}
+
+// withField describes the cause of a request error. Schema errors of the body
+// are reported at their JSON path, everything else at the given field.
+func withField(requestErr *openapi3filter.RequestError, field string) []apierror.FieldError {
+	var fields []apierror.FieldError
+
+	if multiErr, ok := requestErr.Err.(openapi3.MultiError); ok {
+		for _, err := range multiErr {
+			fields = append(fields, schemaFieldError(err, field))
+		}
+
+		return fields
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(requestErr.Err, &schemaErr) {
+		return []apierror.FieldError{schemaFieldError(schemaErr, field)}
+	}
+
+	return []apierror.FieldError{{Field: field, Message: requestMessage(requestErr)}}
+}
+
+func schemaFieldError(err error, field string) apierror.FieldError {
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return apierror.FieldError{Field: field, Message: err.Error()}
+	}
+
+	if path := schemaErr.JSONPointer(); len(path) > 0 && field == `body` {
+		field = strings.Join(path, `.`)
+	}
+
+	message := schemaErr.Reason
+	if message == `` && schemaErr.Origin != nil {
+		message = schemaErr.Origin.Error()
+	}
+
+	return apierror.FieldError{Field: field, Message: message}
+}
+
+func requestMessage(requestErr *openapi3filter.RequestError) string {
+	var parseErr *openapi3filter.ParseError
+
+	switch {
+	case errors.Is(requestErr.Err, openapi3filter.ErrInvalidRequired):
+		return `value is required`
+	case errors.Is(requestErr.Err, openapi3filter.ErrInvalidEmptyValue):
+		return `value must not be empty`
+	case errors.As(requestErr.Err, &parseErr):
+		return `value could not be parsed`
+	case requestErr.Reason != ``:
+		return requestErr.Reason
+	case requestErr.Err != nil:
+		return requestErr.Err.Error()
+	}
+
+	return `invalid value`
+}
@@ -1,21 +1,41 @@
 package tests
 
 import (
+	"avitoBootcamp/internal/auth"
+	"avitoBootcamp/internal/client"
+	"avitoBootcamp/internal/config"
 	"avitoBootcamp/internal/models"
 	"avitoBootcamp/internal/router"
+	"avitoBootcamp/internal/storage"
 	"avitoBootcamp/internal/storage/postgres"
 	"avitoBootcamp/internal/storage/redis"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
+var testKeys, _ = auth.NewKeyring(auth.NewHMACKey(`test`, []byte(`test-secret-test-secret-test-secret`)))
+
+// testConfig points at the ports docker-compose publishes on localhost.
+var testConfig = func() config.Config {
+	cfg := config.Default()
+	cfg.Postgres.Host = `localhost`
+	cfg.Postgres.Port = 5433
+	cfg.Redis.Addr = `localhost:6379`
+
+	return cfg
+}()
+
 func TestGetFlats(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -34,7 +54,7 @@ func TestGetFlats(t *testing.T) {
 			houseId:  1,
 			userType: "client",
 			expectedFlats: []models.Flat{
-				{Id: 2, HouseId: 1, Price: 150000, Rooms: 4, Num: 102, Status: "approved", ModeratorId: 1},
+				{Id: 2, HouseId: 1, Price: 150000, Rooms: 4, Num: 102, Status: "approved", ModeratorId: models.DummyModeratorId},
 			},
 			authorized:      true,
 			expectedCode:    http.StatusOK,
@@ -58,7 +78,7 @@ func TestGetFlats(t *testing.T) {
 			houseId:  1,
 			userType: "client",
 			expectedFlats: []models.Flat{
-				{Id: 2, HouseId: 1, Price: 150000, Rooms: 4, Num: 102, Status: "approved", ModeratorId: 1},
+				{Id: 2, HouseId: 1, Price: 150000, Rooms: 4, Num: 102, Status: "approved", ModeratorId: models.DummyModeratorId},
 			},
 			authorized:      true,
 			expectedCode:    http.StatusOK,
@@ -82,8 +102,8 @@ func TestGetFlats(t *testing.T) {
 			houseId:  1,
 			userType: "moderator",
 			expectedFlats: []models.Flat{
-				{Id: 1, HouseId: 1, Price: 100000, Rooms: 3, Num: 101, Status: "created", ModeratorId: 0},
-				{Id: 2, HouseId: 1, Price: 150000, Rooms: 4, Num: 102, Status: "approved", ModeratorId: 1},
+				{Id: 1, HouseId: 1, Price: 100000, Rooms: 3, Num: 101, Status: "created"},
+				{Id: 2, HouseId: 1, Price: 150000, Rooms: 4, Num: 102, Status: "approved", ModeratorId: models.DummyModeratorId},
 			},
 			authorized:      true,
 			expectedCode:    http.StatusOK,
@@ -94,62 +114,53 @@ func TestGetFlats(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			db, err := postgres.ConnectForTest()
+			db, err := postgres.Connect(testConfig.Postgres)
 			if err != nil {
 				t.Fatalf("Не удалось подключиться к базе данных: %v", err)
 			}
 			defer db.Db.Close()
 
-			cache, err := redis.NewForTest()
+			cache, err := redis.New(testConfig.Redis)
 			if err != nil {
 				t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
 			}
 			defer cache.Client.Close()
 
 			if tc.expectCacheHit {
-				cachedData, _ := json.Marshal(tc.expectedFlats)
-				cacheKey := fmt.Sprintf("houseID:%d,userType:%s", tc.houseId, tc.userType)
+				cachedData, _ := json.Marshal(models.HouseFlatsPage{Flats: tc.expectedFlats})
+				cacheKey := flatsCacheKey(tc.houseId, tc.userType)
 				cache.Client.Set(context.Background(), cacheKey, cachedData, 0)
 			}
 
+			api := newClient(t, db, cache)
+
 			var token string
 			if tc.authorized {
-				token, err = router.PerformLogin(tc.userType)
+				token, err = api.DummyToken(context.Background(), client.UserType(tc.userType))
 				if err != nil {
 					t.Fatalf("Не удалось получить токен: %v", err)
 				}
 			}
 
-			req, err := http.NewRequest("GET", fmt.Sprintf("/house/%d", tc.houseId), nil)
+			response, err := api.GetHouseFlatsWithResponse(context.Background(), tc.houseId, nil, withToken(token))
 			assert.NoError(t, err)
-			req.Header.Set("Content-Type", "application/json")
-			req.Header.Set("Authorization", token)
-
-			rr := httptest.NewRecorder()
-			handler := router.New(db, cache)
-			handler.ServeHTTP(rr, req)
-
-			assert.Equal(t, tc.expectedCode, rr.Code)
+			assert.Equal(t, tc.expectedCode, response.StatusCode())
 
 			if tc.expectedCode == http.StatusOK {
-				assert.NotEmpty(t, rr.Body.Bytes(), "Response body should not be empty")
-
-				var flats []models.Flat
-				err = json.Unmarshal(rr.Body.Bytes(), &flats)
-				assert.NoError(t, err)
-				assert.Equal(t, tc.expectedFlats, flats)
+				assert.NotNil(t, response.JSON200)
+				assert.Equal(t, tc.expectedFlats, response.JSON200.Flats)
 			}
 
 			if tc.expectCacheData {
-				cacheKey := fmt.Sprintf("houseID:%d,userType:%s", tc.houseId, tc.userType)
+				cacheKey := flatsCacheKey(tc.houseId, tc.userType)
 				cachedData, err := cache.Client.Get(context.Background(), cacheKey).Result()
 				assert.NoError(t, err)
 				assert.NotEmpty(t, cachedData, "Cached data should not be empty")
 
-				var cachedFlats []models.Flat
-				err = json.Unmarshal([]byte(cachedData), &cachedFlats)
+				var cachedPage models.HouseFlatsPage
+				err = json.Unmarshal([]byte(cachedData), &cachedPage)
 				assert.NoError(t, err)
-				assert.Equal(t, tc.expectedFlats, cachedFlats)
+				assert.Equal(t, tc.expectedFlats, cachedPage.Flats)
 			}
 		})
 	}
@@ -168,7 +179,7 @@ func TestFlatCreateHandler(t *testing.T) {
 		{
 			name: "Authorized user, successful creation",
 			inputFlat: models.Flat{
-				HouseId: 1, Price: 200000, Rooms: 3, Num: 103, Status: "created", ModeratorId: 1,
+				HouseId: 1, Price: 200000, Rooms: 3, Num: 103, Status: "created",
 			},
 			userType:         "moderator",
 			authorized:       true,
@@ -179,7 +190,7 @@ func TestFlatCreateHandler(t *testing.T) {
 		{
 			name: "Unauthorized access",
 			inputFlat: models.Flat{
-				HouseId: 1, Price: 200000, Rooms: 3, Num: 103, Status: "created", ModeratorId: 1,
+				HouseId: 1, Price: 200000, Rooms: 3, Num: 103, Status: "created",
 			},
 			userType:         "client",
 			authorized:       false,
@@ -195,62 +206,54 @@ func TestFlatCreateHandler(t *testing.T) {
 			expectedCode:     http.StatusBadRequest,
 			expectCacheClear: false,
 		},
-		// Тест 4: Ошибка базы данных при создании квартиры
+		// Тест 4: Дом не существует
 		{
-			name: "Database error",
+			name: "House not found",
 			inputFlat: models.Flat{
-				HouseId: 9999, Price: 200000, Rooms: 3, Num: 103, Status: "created", ModeratorId: 1,
+				HouseId: 9999, Price: 200000, Rooms: 3, Num: 103, Status: "created",
 			},
 			userType:         "moderator",
 			authorized:       true,
-			expectedCode:     http.StatusInternalServerError,
+			expectedCode:     http.StatusNotFound,
 			expectCacheClear: false,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			db, err := postgres.ConnectForTest()
+			db, err := postgres.Connect(testConfig.Postgres)
 			if err != nil {
 				t.Fatalf("Не удалось подключиться к базе данных: %v", err)
 			}
 			defer db.Db.Close()
 
-			cache, err := redis.NewForTest()
+			cache, err := redis.New(testConfig.Redis)
 			if err != nil {
 				t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
 			}
 			defer cache.Client.Close()
 
+			api := newClient(t, db, cache)
+
 			var token string
 			if tc.authorized {
-				token, err = router.PerformLogin(tc.userType)
+				token, err = api.DummyToken(context.Background(), client.UserType(tc.userType))
 				if err != nil {
 					t.Fatalf("Не удалось получить токен: %v", err)
 				}
 			}
 
-			body, err := json.Marshal(tc.inputFlat)
+			var response *client.CreateFlatResponse
 			if tc.name == "Invalid JSON" {
-				body = []byte(`{"invalid_json"`)
+				response, err = api.CreateFlatWithBodyWithResponse(context.Background(), "application/json", strings.NewReader(`{"invalid_json"`), withToken(token))
+			} else {
+				response, err = api.CreateFlatWithResponse(context.Background(), tc.inputFlat, withToken(token))
 			}
 			assert.NoError(t, err)
-
-			req, err := http.NewRequest("POST", "/flat/create", bytes.NewBuffer(body))
-			assert.NoError(t, err)
-			req.Header.Set("Authorization", token)
-			req.Header.Set("Content-Type", "application/json")
-
-			rr := httptest.NewRecorder()
-			handler := router.New(db, cache)
-			handler.ServeHTTP(rr, req)
-
-			assert.Equal(t, tc.expectedCode, rr.Code)
+			assert.Equal(t, tc.expectedCode, response.StatusCode())
 
 			if tc.expectedCode == http.StatusOK {
-				var createdFlat models.Flat
-				err = json.Unmarshal(rr.Body.Bytes(), &createdFlat)
-				assert.NoError(t, err)
+				createdFlat := response.JSON200
 				assert.Equal(t, tc.inputFlat.HouseId, createdFlat.HouseId)
 				assert.Equal(t, tc.inputFlat.Price, createdFlat.Price)
 				assert.Equal(t, tc.inputFlat.Rooms, createdFlat.Rooms)
@@ -258,13 +261,13 @@ func TestFlatCreateHandler(t *testing.T) {
 			}
 
 			if tc.expectCacheClear {
-				cacheKeyModerator := fmt.Sprintf("houseID:%d,userType:moderator", tc.inputFlat.HouseId)
+				cacheKeyModerator := flatsCacheKey(tc.inputFlat.HouseId, "moderator")
 				cachedData, err := cache.Client.Get(context.Background(), cacheKeyModerator).Result()
 				assert.Error(t, err)
 				assert.Empty(t, cachedData)
 
 				if tc.inputFlat.Status == "approved" {
-					cacheKeyClient := fmt.Sprintf("houseID:%d,userType:client", tc.inputFlat.HouseId)
+					cacheKeyClient := flatsCacheKey(tc.inputFlat.HouseId, "client")
 					cachedData, err = cache.Client.Get(context.Background(), cacheKeyClient).Result()
 					assert.Error(t, err)
 					assert.Empty(t, cachedData)
@@ -336,41 +339,39 @@ func TestHouseCreateHandler(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			db, err := postgres.ConnectForTest()
+			db, err := postgres.Connect(testConfig.Postgres)
 			if err != nil {
 				t.Fatalf("Не удалось подключиться к базе данных: %v", err)
 			}
 			defer db.Db.Close()
 
+			cache, err := redis.New(testConfig.Redis)
+			if err != nil {
+				t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
+			}
+			defer cache.Client.Close()
+
+			api := newClient(t, db, cache)
+
 			var token string
 			if tc.authorized {
-				token, err = router.PerformLogin(tc.userType)
+				token, err = api.DummyToken(context.Background(), client.UserType(tc.userType))
 				if err != nil {
 					t.Fatalf("Не удалось получить токен: %v", err)
 				}
 			}
 
-			body, err := json.Marshal(tc.inputHouse)
+			var response *client.CreateHouseResponse
 			if tc.name == "Invalid JSON" {
-				body = []byte(`{"invalid_json"`)
+				response, err = api.CreateHouseWithBodyWithResponse(context.Background(), "application/json", strings.NewReader(`{"invalid_json"`), withToken(token))
+			} else {
+				response, err = api.CreateHouseWithResponse(context.Background(), tc.inputHouse, withToken(token))
 			}
 			assert.NoError(t, err)
-
-			req, err := http.NewRequest("POST", "/house/create", bytes.NewBuffer(body))
-			assert.NoError(t, err)
-			req.Header.Set("Authorization", token)
-			req.Header.Set("Content-Type", "application/json")
-
-			rr := httptest.NewRecorder()
-			handler := router.New(db, nil)
-			handler.ServeHTTP(rr, req)
-
-			assert.Equal(t, tc.expectedCode, rr.Code)
+			assert.Equal(t, tc.expectedCode, response.StatusCode())
 
 			if tc.expectedCode == http.StatusOK {
-				var createdHouse models.House
-				err = json.Unmarshal(rr.Body.Bytes(), &createdHouse)
-				assert.NoError(t, err)
+				createdHouse := response.JSON200
 				assert.Equal(t, tc.inputHouse.Address, createdHouse.Address)
 				assert.Equal(t, tc.inputHouse.Year, createdHouse.Year)
 				assert.Equal(t, tc.inputHouse.Developer, createdHouse.Developer)
@@ -388,30 +389,29 @@ func TestFlatUpdateHandler(t *testing.T) {
 		expectedCode     int
 		expectedFlat     models.Flat
 		expectCacheClear bool
+		otherModerator   bool
 	}{
-		// Тест 1: Успешное обновление квартиры, авторизованный модератор
+		// Тест 1: Квартиру нельзя одобрить, не взяв ее на модерацию
 		{
-			name: "Authorized moderator, successful flat update",
+			name: "Approving without moderation",
 			inputFlat: models.Flat{
-				Id: 1, Status: "approved", ModeratorId: 3,
+				Id: 1, Status: "approved",
 			},
-			userType:     "moderator",
-			authorized:   true,
-			expectedCode: http.StatusOK,
-			expectedFlat: models.Flat{
-				Id: 1, HouseId: 1, Price: 100000, Rooms: 3, Num: 101, Status: "approved", ModeratorId: 0,
-			},
-			expectCacheClear: true,
+			userType:         "moderator",
+			authorized:       true,
+			expectedCode:     http.StatusConflict,
+			expectedFlat:     models.Flat{},
+			expectCacheClear: false,
 		},
-		// Тест 2: Неавторизованный запрос
+		// Тест 2: Клиент не может менять статус квартиры
 		{
-			name: "Unauthorized access",
+			name: "Client can not moderate",
 			inputFlat: models.Flat{
-				Id: 1, Status: "approved", ModeratorId: 21,
+				Id: 1, Status: "approved",
 			},
 			userType:         "client",
 			authorized:       true,
-			expectedCode:     http.StatusUnauthorized,
+			expectedCode:     http.StatusForbidden,
 			expectedFlat:     models.Flat{},
 			expectCacheClear: false,
 		},
@@ -419,7 +419,7 @@ func TestFlatUpdateHandler(t *testing.T) {
 		{
 			name: "Invalid JSON",
 			inputFlat: models.Flat{
-				Id: 1, Status: "approved", ModeratorId: 10,
+				Id: 1, Status: "approved",
 			},
 			userType:         "moderator",
 			authorized:       true,
@@ -427,15 +427,15 @@ func TestFlatUpdateHandler(t *testing.T) {
 			expectedFlat:     models.Flat{},
 			expectCacheClear: false,
 		},
-		// Тест 4: Ошибка базы данных при обновлении квартиры
+		// Тест 4: Обновление несуществующей квартиры
 		{
-			name: "Database update error",
+			name: "Flat not found",
 			inputFlat: models.Flat{
-				Id: 9999, Status: "approved", ModeratorId: 0,
+				Id: 9999, Status: "approved",
 			},
 			userType:         "moderator",
 			authorized:       true,
-			expectedCode:     http.StatusInternalServerError,
+			expectedCode:     http.StatusNotFound,
 			expectedFlat:     models.Flat{},
 			expectCacheClear: false,
 		},
@@ -443,13 +443,13 @@ func TestFlatUpdateHandler(t *testing.T) {
 		{
 			name: "Changing status to “on moderation”",
 			inputFlat: models.Flat{
-				Id: 1, Status: "on moderation", ModeratorId: 13,
+				Id: 1, Status: "on moderation",
 			},
 			userType:     "moderator",
 			authorized:   true,
 			expectedCode: http.StatusOK,
 			expectedFlat: models.Flat{
-				Id: 1, HouseId: 1, Price: 100000, Rooms: 3, Num: 101, Status: "on moderation", ModeratorId: 13,
+				Id: 1, HouseId: 1, Price: 100000, Rooms: 3, Num: 101, Status: "on moderation", ModeratorId: models.DummyModeratorId,
 			},
 			expectCacheClear: false,
 		},
@@ -457,11 +457,12 @@ func TestFlatUpdateHandler(t *testing.T) {
 		{
 			name: "Changing status to “on moderation”",
 			inputFlat: models.Flat{
-				Id: 1, Status: "declined", ModeratorId: 47,
+				Id: 1, Status: "declined", DeclineReasonCode: "other", DeclineReason: "Нет фотографий",
 			},
 			userType:         "moderator",
 			authorized:       true,
-			expectedCode:     http.StatusUnauthorized,
+			otherModerator:   true,
+			expectedCode:     http.StatusConflict,
 			expectedFlat:     models.Flat{},
 			expectCacheClear: false,
 		},
@@ -469,13 +470,14 @@ func TestFlatUpdateHandler(t *testing.T) {
 		{
 			name: "Changing status to “declined”",
 			inputFlat: models.Flat{
-				Id: 1, Status: "declined", ModeratorId: 13,
+				Id: 1, Status: "declined", DeclineReasonCode: "other", DeclineReason: "Нет фотографий",
 			},
 			userType:     "moderator",
 			authorized:   true,
 			expectedCode: http.StatusOK,
 			expectedFlat: models.Flat{
-				Id: 1, HouseId: 1, Price: 100000, Rooms: 3, Num: 101, Status: "declined", ModeratorId: 13,
+				Id: 1, HouseId: 1, Price: 100000, Rooms: 3, Num: 101, Status: "declined", ModeratorId: models.DummyModeratorId,
+				DeclineReasonCode: "other", DeclineReason: "Нет фотографий",
 			},
 			expectCacheClear: false,
 		},
@@ -483,58 +485,60 @@ func TestFlatUpdateHandler(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			db, err := postgres.ConnectForTest()
+			db, err := postgres.Connect(testConfig.Postgres)
 			if err != nil {
 				t.Fatalf("Не удалось подключиться к базе данных: %v", err)
 			}
 			defer db.Db.Close()
 
-			cache, err := redis.NewForTest()
+			cache, err := redis.New(testConfig.Redis)
 			if err != nil {
 				t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
 			}
 			defer cache.Client.Close()
 
+			api := newClient(t, db, cache)
+
 			var token string
 			if tc.authorized {
-				token, err = router.PerformLogin(tc.userType)
+				token, err = api.DummyToken(context.Background(), client.UserType(tc.userType))
 				if err != nil {
 					t.Fatalf("Не удалось получить токен: %v", err)
 				}
 			}
 
-			body, err := json.Marshal(tc.inputFlat)
-			if tc.name == "Invalid JSON" {
-				body = []byte(`{"invalid_json"`)
+			if tc.otherModerator {
+				token = loginAsNewUser(t, db, tc.userType)
 			}
-			assert.NoError(t, err)
 
-			req, err := http.NewRequest("POST", "/flat/update", bytes.NewBuffer(body))
+			var response *client.UpdateFlatResponse
+			if tc.name == "Invalid JSON" {
+				response, err = api.UpdateFlatWithBodyWithResponse(context.Background(), "application/json", strings.NewReader(`{"invalid_json"`), withToken(token))
+			} else {
+				response, err = api.UpdateFlatWithResponse(context.Background(), tc.inputFlat, withToken(token))
+			}
 			assert.NoError(t, err)
-			req.Header.Set("Authorization", token)
-			req.Header.Set("Content-Type", "application/json")
+			assert.Equal(t, tc.expectedCode, response.StatusCode())
 
-			rr := httptest.NewRecorder()
-			handler := router.New(db, cache)
-			handler.ServeHTTP(rr, req)
+			if tc.expectedCode == http.StatusOK {
+				updatedFlat := *response.JSON200
 
-			assert.Equal(t, tc.expectedCode, rr.Code)
+				if updatedFlat.Status == "on moderation" {
+					assert.NotNil(t, updatedFlat.ModerationExpiresAt)
+				}
+				updatedFlat.ModerationExpiresAt = nil
 
-			if tc.expectedCode == http.StatusOK {
-				var updatedFlat models.Flat
-				err = json.Unmarshal(rr.Body.Bytes(), &updatedFlat)
-				assert.NoError(t, err)
 				assert.Equal(t, tc.expectedFlat, updatedFlat)
 			}
 
 			if tc.expectCacheClear {
-				cacheKeyModerator := fmt.Sprintf("houseID:%d,userType:moderator", tc.inputFlat.HouseId)
+				cacheKeyModerator := flatsCacheKey(tc.inputFlat.HouseId, "moderator")
 				cachedData, err := cache.Client.Get(context.Background(), cacheKeyModerator).Result()
 				assert.Error(t, err)
 				assert.Empty(t, cachedData)
 
 				if tc.inputFlat.Status == "approved" {
-					cacheKeyClient := fmt.Sprintf("houseID:%d,userType:client", tc.inputFlat.HouseId)
+					cacheKeyClient := flatsCacheKey(tc.inputFlat.HouseId, "client")
 					cachedData, err = cache.Client.Get(context.Background(), cacheKeyClient).Result()
 					assert.Error(t, err)
 					assert.Empty(t, cachedData)
@@ -543,3 +547,742 @@ func TestFlatUpdateHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestFlatModerationLease(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	cache, err := redis.New(testConfig.Redis)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
+	}
+	defer cache.Client.Close()
+
+	flat, err := db.CreateFlat(context.Background(), models.Flat{HouseId: 1, Price: 100000, Rooms: 2, Num: int(time.Now().UnixNano() % 1000000000), OwnerId: models.DummyClientId})
+	if err != nil {
+		t.Fatalf("Не удалось создать квартиру: %v", err)
+	}
+
+	api := newClient(t, db, cache)
+	owner := loginAsNewUser(t, db, "moderator")
+	other := loginAsNewUser(t, db, "moderator")
+
+	take := func(token string) int {
+		response, err := api.UpdateFlatWithResponse(context.Background(), models.Flat{Id: flat.Id, Status: models.StatusOnModeration}, withToken(token))
+		assert.NoError(t, err)
+
+		return response.StatusCode()
+	}
+
+	extend := func(token string) int {
+		response, err := api.ExtendFlatWithResponse(context.Background(), flat.Id, withToken(token))
+		assert.NoError(t, err)
+
+		return response.StatusCode()
+	}
+
+	release := func(token string) int {
+		response, err := api.ReleaseFlatWithResponse(context.Background(), flat.Id, withToken(token))
+		assert.NoError(t, err)
+
+		return response.StatusCode()
+	}
+
+	// Тест 1: Модератор берет квартиру и продлевает блокировку
+	assert.Equal(t, http.StatusOK, take(owner))
+	assert.Equal(t, http.StatusOK, extend(owner))
+
+	// Продление блокировки не меняет статус и не попадает в историю
+	history, err := db.GetFlatStatusHistory(context.Background(), flat.Id)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, models.StatusOnModeration, history[1].NewStatus)
+	}
+
+	// Тест 2: Пока блокировка действует, другой модератор не может забрать квартиру
+	assert.Equal(t, http.StatusConflict, take(other))
+	assert.Equal(t, http.StatusConflict, release(other))
+
+	// Тест 3: После истечения блокировки квартиру забирает другой модератор
+	_, err = db.Db.Exec(`UPDATE flat SET moderation_expires_at = now() - interval '1 second' WHERE id = $1`, flat.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, take(other))
+	assert.Equal(t, http.StatusConflict, extend(owner))
+
+	// Тест 4: Новый модератор возвращает квартиру в очередь
+	assert.Equal(t, http.StatusOK, release(other))
+	assert.Equal(t, http.StatusConflict, extend(other))
+}
+
+func TestModerationQueueNext(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	cache, err := redis.New(testConfig.Redis)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
+	}
+	defer cache.Client.Close()
+
+	house, err := db.CreateHouse(context.Background(), models.House{Address: "Очередь модерации, 1", Year: 2024})
+	if err != nil {
+		t.Fatalf("Не удалось создать дом: %v", err)
+	}
+
+	for num := 1; num <= 2; num++ {
+		if _, err := db.CreateFlat(context.Background(), models.Flat{HouseId: house.Id, Price: 100000, Rooms: 2, Num: num, OwnerId: models.DummyClientId}); err != nil {
+			t.Fatalf("Не удалось создать квартиру: %v", err)
+		}
+	}
+
+	api := newClient(t, db, cache)
+
+	takeNext := func(token string) (int, models.Flat) {
+		response, err := api.TakeNextFlatWithResponse(context.Background(), &client.TakeNextFlatParams{HouseId: &house.Id}, withToken(token))
+		assert.NoError(t, err)
+
+		var flat models.Flat
+		if response.JSON200 != nil {
+			flat = *response.JSON200
+		}
+
+		return response.StatusCode(), flat
+	}
+
+	// Тест 1: Два модератора одновременно получают разные квартиры
+	tokens := []string{loginAsNewUser(t, db, "moderator"), loginAsNewUser(t, db, "moderator")}
+	taken := make([]models.Flat, len(tokens))
+
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var code int
+			code, taken[i] = takeNext(token)
+			assert.Equal(t, http.StatusOK, code)
+		}()
+	}
+	wg.Wait()
+
+	assert.NotEqual(t, taken[0].Id, taken[1].Id)
+
+	// Тест 2: Очередь дома пуста
+	code, _ := takeNext(tokens[0])
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// Тест 3: Квартира с истекшей блокировкой возвращается в очередь
+	_, err = db.Db.Exec(`UPDATE flat SET moderation_expires_at = now() - interval '1 second' WHERE id = $1`, taken[0].Id)
+	assert.NoError(t, err)
+
+	queue, err := db.GetModerationQueue(context.Background(), models.ModerationQueueFilter{HouseId: house.Id, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{taken[0].Id}, flatIds(queue.Flats))
+
+	code, flat := takeNext(tokens[1])
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, taken[0].Id, flat.Id)
+
+	code, _ = takeNext(tokens[0])
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestFlatEdit(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	cache, err := redis.New(testConfig.Redis)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
+	}
+	defer cache.Client.Close()
+
+	house, err := db.CreateHouse(context.Background(), models.House{Address: "Редактирование квартир, 1", Year: 2024})
+	if err != nil {
+		t.Fatalf("Не удалось создать дом: %v", err)
+	}
+
+	api := newClient(t, db, cache)
+	owner := loginAsNewUser(t, db, "client")
+
+	var flats []models.Flat
+	for num := 1; num <= 2; num++ {
+		response, err := api.CreateFlatWithResponse(context.Background(), models.Flat{HouseId: house.Id, Price: 100000, Rooms: 2, Num: num}, withToken(owner))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode())
+		flats = append(flats, *response.JSON200)
+	}
+
+	editFlat := func(token string, edit models.FlatEdit) (int, models.Flat) {
+		response, err := api.EditFlatWithResponse(context.Background(), flats[1].Id, edit, withToken(token))
+		assert.NoError(t, err)
+
+		var flat models.Flat
+		if response.JSON200 != nil {
+			flat = *response.JSON200
+		}
+
+		return response.StatusCode(), flat
+	}
+
+	// Тест 1: Номер квартиры уже занят в доме
+	code, _ := editFlat(owner, models.FlatEdit{Num: ptr(1)})
+	assert.Equal(t, http.StatusConflict, code)
+
+	// Тест 2: Чужую квартиру изменить нельзя
+	code, _ = editFlat(loginAsNewUser(t, db, "client"), models.FlatEdit{Price: ptr[int64](1)})
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// Тест 3: Одобренная квартира после изменения возвращается на модерацию
+	_, err = db.Db.Exec(`UPDATE flat SET status = 'approved' WHERE id = $1`, flats[1].Id)
+	assert.NoError(t, err)
+
+	code, flat := editFlat(owner, models.FlatEdit{Price: ptr[int64](120000), Num: ptr(3)})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.StatusCreated, flat.Status)
+	assert.Equal(t, int64(120000), flat.Price)
+	assert.Equal(t, 3, flat.Num)
+
+	// Тест 4: Квартиру на модерации не изменить, пока действует блокировка
+	moderator := withToken(loginAsNewUser(t, db, "moderator"))
+	taken, err := api.UpdateFlatWithResponse(context.Background(), models.Flat{Id: flats[1].Id, Status: models.StatusOnModeration}, moderator)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, taken.StatusCode())
+
+	code, _ = editFlat(owner, models.FlatEdit{Price: ptr[int64](1)})
+	assert.Equal(t, http.StatusConflict, code)
+
+	// Тест 5: После истечения блокировки изменение снова разрешено
+	_, err = db.Db.Exec(`UPDATE flat SET moderation_expires_at = now() - interval '1 second' WHERE id = $1`, flats[1].Id)
+	assert.NoError(t, err)
+
+	code, _ = editFlat(owner, models.FlatEdit{Price: ptr[int64](130000)})
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestMyFlats(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	owner, err := db.CreateUser(context.Background(), models.User{
+		Email:    fmt.Sprintf("owner-%d@test.com", time.Now().UnixNano()),
+		Password: "unused",
+		UserType: "client",
+	})
+	if err != nil {
+		t.Fatalf("Не удалось создать пользователя: %v", err)
+	}
+
+	house, err := db.CreateHouse(context.Background(), models.House{Address: "Мои квартиры, 1", Year: 2024})
+	if err != nil {
+		t.Fatalf("Не удалось создать дом: %v", err)
+	}
+
+	var flats []models.Flat
+	for num := 1; num <= 2; num++ {
+		flat, err := db.CreateFlat(context.Background(), models.Flat{HouseId: house.Id, Price: 100000, Rooms: 2, Num: num, OwnerId: owner.Id})
+		if err != nil {
+			t.Fatalf("Не удалось создать квартиру: %v", err)
+		}
+
+		flats = append(flats, flat)
+	}
+
+	_, err = db.DeleteFlat(context.Background(), flats[0].Id)
+	assert.NoError(t, err)
+
+	// Тест 1: Квартиры в архиве не возвращаются
+	mine, err := db.GetFlatsByOwner(context.Background(), owner.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{flats[1].Id}, flatIds(mine))
+}
+
+func TestHouseEditAndList(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	cache, err := redis.New(testConfig.Redis)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
+	}
+	defer cache.Client.Close()
+
+	developer := fmt.Sprintf("Застройщик %d", time.Now().UnixNano())
+	api := newClient(t, db, cache)
+	moderator := withToken(loginAsNewUser(t, db, "moderator"))
+
+	var houses []models.House
+	for year := 2000; year <= 2002; year++ {
+		house, err := db.CreateHouse(context.Background(), models.House{Address: "Список домов, 1", Year: year, Developer: developer})
+		if err != nil {
+			t.Fatalf("Не удалось создать дом: %v", err)
+		}
+
+		houses = append(houses, house)
+	}
+
+	listHouses := func(params client.ListHousesParams) models.HousePage {
+		response, err := api.ListHousesWithResponse(context.Background(), &params, moderator)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode())
+
+		if response.JSON200 == nil {
+			return models.HousePage{}
+		}
+
+		return *response.JSON200
+	}
+
+	// Тест 1: Изменяются только переданные поля
+	edited, err := api.EditHouseWithResponse(context.Background(), houses[0].Id, models.HouseEdit{Address: ptr("Список домов, 2")}, moderator)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, edited.StatusCode())
+
+	info, err := api.GetHouseWithResponse(context.Background(), houses[0].Id, moderator)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, info.StatusCode())
+
+	house := info.JSON200
+	assert.Equal(t, "Список домов, 2", house.Address)
+	assert.Equal(t, 2000, house.Year)
+	assert.Equal(t, developer, house.Developer)
+
+	// Тест 2: Фильтр по годам и постраничный вывод
+	params := client.ListHousesParams{Developer: &developer, YearMin: ptr(2001), Limit: ptr(1)}
+	page := listHouses(params)
+	assert.Equal(t, []int64{houses[1].Id}, houseIds(page.Houses))
+	assert.NotEmpty(t, page.NextCursor)
+
+	params.Cursor = &page.NextCursor
+	page = listHouses(params)
+	assert.Equal(t, []int64{houses[2].Id}, houseIds(page.Houses))
+	assert.Empty(t, page.NextCursor)
+
+	// Тест 3: Только дома, в которых появились новые квартиры
+	since := time.Now().UTC().Add(-time.Second).Truncate(time.Second)
+	created, err := api.CreateFlatWithResponse(context.Background(), models.Flat{HouseId: houses[2].Id, Price: 100000, Rooms: 2, Num: 1}, moderator)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, created.StatusCode())
+
+	page = listHouses(client.ListHousesParams{Developer: &developer, UpdatedSince: &since})
+	assert.Equal(t, []int64{houses[2].Id}, houseIds(page.Houses))
+}
+
+func TestSubscription(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	cache, err := redis.New(testConfig.Redis)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
+	}
+	defer cache.Client.Close()
+
+	api := newClient(t, db, cache)
+	subscriber := withToken(loginAsNewUser(t, db, "client"))
+	subscription := models.Subscription{Email: "subscriber@test.com"}
+
+	house, err := db.CreateHouse(context.Background(), models.House{Address: "Подписка, 1", Year: 2000})
+	if err != nil {
+		t.Fatalf("Не удалось создать дом: %v", err)
+	}
+
+	// Тест 1: Подписка на существующий дом
+	response, err := api.SubscribeWithResponse(context.Background(), house.Id, subscription, subscriber)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	// Тест 2: Дом не существует
+	response, err = api.SubscribeWithResponse(context.Background(), house.Id+1000000, subscription, subscriber)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode())
+
+	// Тест 3: Дом в архиве
+	_, err = db.DeleteHouse(context.Background(), house.Id)
+	assert.NoError(t, err)
+
+	response, err = api.SubscribeWithResponse(context.Background(), house.Id, subscription, subscriber)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode())
+}
+
+func TestFlatSearch(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	cache, err := redis.New(testConfig.Redis)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
+	}
+	defer cache.Client.Close()
+
+	town := fmt.Sprintf("Springfield%d", time.Now().UnixNano())
+	house, err := db.CreateHouse(context.Background(), models.House{Address: "Evergreen Terrace 742, " + town, Year: 2015})
+	if err != nil {
+		t.Fatalf("Не удалось создать дом: %v", err)
+	}
+
+	oldHouse, err := db.CreateHouse(context.Background(), models.House{Address: "Main Street 1, " + town, Year: 1990})
+	if err != nil {
+		t.Fatalf("Не удалось создать дом: %v", err)
+	}
+
+	api := newClient(t, db, cache)
+	clientToken := withToken(loginAsNewUser(t, db, "client"))
+
+	var approved []int64
+	for num, price := range []int64{140000, 120000, 130000} {
+		flat, err := db.CreateFlat(context.Background(), models.Flat{HouseId: house.Id, Price: price, Rooms: 3, Num: num + 1, OwnerId: models.DummyClientId})
+		assert.NoError(t, err)
+		approved = append(approved, flat.Id)
+	}
+
+	_, err = db.Db.Exec(`UPDATE flat SET status = 'approved' WHERE id = ANY($1)`, pq.Array(approved))
+	assert.NoError(t, err)
+
+	// Не подходят: квартира на модерации, дорогая квартира и квартира в старом доме
+	_, err = db.CreateFlat(context.Background(), models.Flat{HouseId: house.Id, Price: 100000, Rooms: 3, Num: 4, OwnerId: models.DummyClientId})
+	assert.NoError(t, err)
+
+	expensive, err := db.CreateFlat(context.Background(), models.Flat{HouseId: house.Id, Price: 200000, Rooms: 3, Num: 5, OwnerId: models.DummyClientId})
+	assert.NoError(t, err)
+
+	old, err := db.CreateFlat(context.Background(), models.Flat{HouseId: oldHouse.Id, Price: 100000, Rooms: 3, Num: 1, OwnerId: models.DummyClientId})
+	assert.NoError(t, err)
+
+	_, err = db.Db.Exec(`UPDATE flat SET status = 'approved' WHERE id = ANY($1)`, pq.Array([]int64{expensive.Id, old.Id}))
+	assert.NoError(t, err)
+
+	search := func(params client.SearchFlatsParams) models.FlatSearchPage {
+		response, err := api.SearchFlatsWithResponse(context.Background(), &params, clientToken)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode())
+
+		if response.JSON200 == nil {
+			return models.FlatSearchPage{}
+		}
+
+		return *response.JSON200
+	}
+
+	params := client.SearchFlatsParams{
+		Address:  ptr(strings.ToLower(town)),
+		RoomsMin: ptr(3),
+		RoomsMax: ptr(3),
+		PriceMax: ptr[int64](150000),
+		YearMin:  ptr(2010),
+		Sort:     ptr(client.SearchFlatsParamsSortPriceAsc),
+		Limit:    ptr(2),
+	}
+
+	// Тест 1: Клиент находит только одобренные квартиры по всем фильтрам, по возрастанию цены
+	page := search(params)
+	assert.Equal(t, []int64{approved[1], approved[2]}, flatIds(page.Flats))
+	assert.NotEmpty(t, page.NextCursor)
+
+	// Тест 2: Следующая страница продолжает с места остановки
+	params.Cursor = &page.NextCursor
+	page = search(params)
+	assert.Equal(t, []int64{approved[0]}, flatIds(page.Flats))
+	assert.Empty(t, page.NextCursor)
+}
+
+func flatIds(flats []models.Flat) []int64 {
+	ids := make([]int64, 0, len(flats))
+	for _, flat := range flats {
+		ids = append(ids, flat.Id)
+	}
+
+	return ids
+}
+
+func houseIds(houses []models.House) []int64 {
+	ids := make([]int64, 0, len(houses))
+	for _, house := range houses {
+		ids = append(ids, house.Id)
+	}
+
+	return ids
+}
+
+func TestHouseFlatsPaging(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	cache, err := redis.New(testConfig.Redis)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к клиенту redis: %v", err)
+	}
+	defer cache.Client.Close()
+
+	house, err := db.CreateHouse(context.Background(), models.House{Address: "Постраничный вывод, 1", Year: 2024})
+	if err != nil {
+		t.Fatalf("Не удалось создать дом: %v", err)
+	}
+
+	var flats []models.Flat
+	for num, price := range []int64{300000, 100000, 200000} {
+		flat, err := db.CreateFlat(context.Background(), models.Flat{HouseId: house.Id, Price: price, Rooms: 2, Num: num + 1, OwnerId: models.DummyClientId})
+		assert.NoError(t, err)
+		flats = append(flats, flat)
+	}
+
+	api := newClient(t, db, cache)
+	token, err := api.DummyToken(context.Background(), client.UserTypeModerator)
+	assert.NoError(t, err)
+	moderator := withToken(token)
+
+	get := func(params client.GetHouseFlatsParams) ([]int64, string) {
+		response, err := api.GetHouseFlatsWithResponse(context.Background(), house.Id, &params, moderator)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode())
+
+		if response.JSON200 == nil {
+			return nil, ``
+		}
+
+		return flatIds(response.JSON200.Flats), response.JSON200.NextCursor
+	}
+
+	// Тест 1: Первая страница по возрастанию цены
+	params := client.GetHouseFlatsParams{Sort: ptr(client.GetHouseFlatsParamsSortPrice), Limit: ptr(2)}
+	ids, cursor := get(params)
+	assert.Equal(t, []int64{flats[1].Id, flats[2].Id}, ids)
+	assert.NotEmpty(t, cursor)
+
+	// Тест 2: Следующая страница
+	params.Cursor = &cursor
+	ids, next := get(params)
+	assert.Equal(t, []int64{flats[0].Id}, ids)
+	assert.Empty(t, next)
+
+	// Тест 3: Новая квартира удаляет из кэша все страницы дома
+	pattern := fmt.Sprintf("houseID:%d,userType:moderator,query:*", house.Id)
+	keys, err := cache.Client.Keys(context.Background(), pattern).Result()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	created, err := api.CreateFlatWithResponse(context.Background(), models.Flat{HouseId: house.Id, Price: 100000, Rooms: 2, Num: 4}, moderator)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, created.StatusCode())
+
+	keys, err = cache.Client.Keys(context.Background(), pattern).Result()
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+// newClient serves the router on a test server and returns a typed client
+// for it.
+func newClient(t *testing.T, db *postgres.Storage, cache *redis.RedisCache) *client.ClientWithResponses {
+	server := httptest.NewServer(router.New(db, cache, testKeys, testConfig.Auth))
+	t.Cleanup(server.Close)
+
+	api, err := client.NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("Не удалось создать клиент: %v", err)
+	}
+
+	return api
+}
+
+// withToken authorizes a request, an empty token sends none.
+func withToken(token string) client.RequestEditorFn {
+	if token == `` {
+		return func(ctx context.Context, req *http.Request) error { return nil }
+	}
+
+	return client.WithBearerToken(token)
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// flatsCacheKey is the cache key of the house flats requested without paging,
+// sorting or filters.
+func flatsCacheKey(houseId int64, userType string) string {
+	query := models.HouseFlatsQuery{Sort: models.HouseFlatsSortFlatNum}
+
+	return fmt.Sprintf("houseID:%d,userType:%s,query:%s", houseId, userType, query.CacheKey())
+}
+
+// loginAsNewUser registers a new user and returns a token for it, for the
+// cases where the shared /dummyLogin user is not enough.
+func loginAsNewUser(t *testing.T, db *postgres.Storage, userType string) string {
+	user, err := db.CreateUser(context.Background(), models.User{
+		Email:    fmt.Sprintf("%s-%d@test.com", userType, time.Now().UnixNano()),
+		Password: "unused",
+		UserType: userType,
+	})
+	if err != nil {
+		t.Fatalf("Не удалось создать пользователя: %v", err)
+	}
+
+	token, err := testKeys.Sign(&models.CustomClaims{
+		UserId: user.Id,
+		Type:   user.UserType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Не удалось получить токен: %v", err)
+	}
+
+	return token
+}
+
+func TestMigrations(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	// Тест 1: Одновременные запуски применяют миграции один раз
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, db.MigrateUp(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	// Тест 2: Все встроенные миграции применены
+	states, err := db.MigrationStatus(context.Background())
+	assert.NoError(t, err)
+	for _, state := range states {
+		assert.NotNil(t, state.AppliedAt, "migration %d", state.Version)
+	}
+
+	// Тест 3: Тестовые данные не добавляются повторно
+	seeded, err := db.Seed(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, seeded)
+}
+
+func TestUserAdministration(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	email := fmt.Sprintf("admin-%d@test.com", time.Now().UnixNano())
+	user, err := db.CreateUser(context.Background(), models.User{Email: email, Password: "old", UserType: "client"})
+	if err != nil {
+		t.Fatalf("Не удалось создать пользователя: %v", err)
+	}
+
+	_, refreshTokenHash, err := auth.NewRefreshToken()
+	assert.NoError(t, err)
+	assert.NoError(t, db.CreateRefreshToken(context.Background(), models.RefreshToken{Hash: refreshTokenHash, UserId: user.Id, ExpiresAt: time.Now().Add(time.Hour)}))
+
+	// Тест 1: Смена пароля отзывает refresh токены
+	_, err = db.SetUserPassword(context.Background(), email, "new")
+	assert.NoError(t, err)
+	stored, err := db.GetUserByEmail(context.Background(), email)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", stored.Password)
+	var revoked bool
+	assert.NoError(t, db.Db.QueryRow(`SELECT revoked_at IS NOT NULL FROM refresh_token WHERE user_id = $1`, user.Id).Scan(&revoked))
+	assert.True(t, revoked)
+
+	// Тест 2: Повышение до модератора
+	_, err = db.SetUserType(context.Background(), email, "moderator")
+	assert.NoError(t, err)
+	stored, err = db.GetUserByEmail(context.Background(), email)
+	assert.NoError(t, err)
+	assert.Equal(t, "moderator", stored.UserType)
+
+	// Тест 3: Неизвестный пользователь
+	_, err = db.SetUserType(context.Background(), "missing-"+email, "client")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Тест 4: Email уже зарегистрирован
+	_, err = db.CreateUser(context.Background(), models.User{Email: email, Password: "other", UserType: "client"})
+	assert.ErrorIs(t, err, storage.ErrEmailTaken)
+}
+
+func TestFlatsCacheAdministration(t *testing.T) {
+	cache, err := redis.New(testConfig.Redis)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к redis: %v", err)
+	}
+	defer cache.Client.Close()
+
+	ctx := context.Background()
+	houseId := time.Now().UnixNano() % 1000000000
+	key := flatsCacheKey(houseId, "client")
+	otherKey := flatsCacheKey(houseId+1, "client")
+	assert.NoError(t, cache.Client.Set(ctx, key, "[]", time.Minute).Err())
+	assert.NoError(t, cache.Client.Set(ctx, otherKey, "[]", time.Minute).Err())
+	defer cache.Client.Del(ctx, otherKey)
+
+	// Тест 1: Просмотр ключей одного дома
+	entries, err := cache.FlatsCacheEntries(context.Background(), houseId)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, key, entries[0].Key)
+		assert.Greater(t, entries[0].TTL, time.Duration(0))
+	}
+
+	// Тест 2: Сброс кэша одного дома не трогает другие дома
+	deleted, err := cache.FlushFlatsCache(context.Background(), houseId)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, int64(1), cache.Client.Exists(ctx, otherKey).Val())
+}
+
+func TestReadiness(t *testing.T) {
+	db, err := postgres.Connect(testConfig.Postgres)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer db.Db.Close()
+
+	cache, err := redis.New(testConfig.Redis)
+	if err != nil {
+		t.Fatalf("Не удалось подключиться к redis: %v", err)
+	}
+
+	api := newClient(t, db, cache)
+
+	// Тест 1: База данных и кэш доступны
+	resp, err := api.ReadyzWithResponse(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	// Тест 2: После закрытия кэша сервис не готов
+	assert.NoError(t, cache.Client.Close())
+	resp, err = api.ReadyzWithResponse(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+}